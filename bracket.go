@@ -0,0 +1,95 @@
+package kjconv
+
+import (
+	"unicode/utf8"
+)
+
+// bracketPairs maps opening brackets to their closing counterparts.
+var bracketPairs = map[rune]rune{
+	'「': '」',
+	'『': '』',
+	'（': '）',
+	'【': '】',
+	'〈': '〉',
+	'《': '》',
+	'“': '”',
+	'(': ')',
+}
+
+// quoteBrackets are the opening brackets whose content is a quotation.
+var quoteBrackets = map[rune]bool{
+	'「': true,
+	'『': true,
+}
+
+// bracketSpan is a matched pair of brackets in a text.
+type bracketSpan struct {
+	Start int  // 開き括弧の位置（バイト）
+	End   int  // 閉じ括弧の直後の位置（バイト）
+	Open  rune // 開き括弧
+}
+
+// isQuote reports whether the span is a quotation (「」 or 『』).
+func (s bracketSpan) isQuote() bool {
+	return quoteBrackets[s.Open]
+}
+
+// contains reports whether the byte offset lies strictly inside the span.
+func (s bracketSpan) contains(offset int) bool {
+	return s.Start < offset && offset < s.End
+}
+
+// scanBrackets finds all matched bracket pairs in text using a stack, ordered by
+// the position of the opening bracket. A closing bracket closes the nearest open
+// bracket of the same type; brackets that are never closed, and closing brackets
+// without an opening one, are ignored.
+func scanBrackets(text string) []bracketSpan {
+	type open struct {
+		index int // spans 内の位置
+		close rune
+	}
+	var spans []bracketSpan
+	var stack []open
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if closing, ok := bracketPairs[r]; ok {
+			spans = append(spans, bracketSpan{Start: i, End: -1, Open: r})
+			stack = append(stack, open{index: len(spans) - 1, close: closing})
+		} else {
+			for j := len(stack) - 1; j >= 0; j-- {
+				if stack[j].close == r {
+					spans[stack[j].index].End = i + size
+					stack = stack[:j]
+					break
+				}
+			}
+		}
+		i += size
+	}
+
+	// Drop brackets that were never closed
+	matched := spans[:0]
+	for _, s := range spans {
+		if s.End >= 0 {
+			matched = append(matched, s)
+		}
+	}
+	return matched
+}
+
+// outermostSpans returns the spans that satisfy keep and are not nested in
+// another such span.
+func outermostSpans(spans []bracketSpan, keep func(bracketSpan) bool) []bracketSpan {
+	var result []bracketSpan
+	for _, s := range spans {
+		if !keep(s) {
+			continue
+		}
+		if len(result) > 0 && result[len(result)-1].contains(s.Start) {
+			continue
+		}
+		result = append(result, s)
+	}
+	return result
+}
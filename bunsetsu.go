@@ -0,0 +1,146 @@
+package kjconv
+
+import (
+	"strings"
+)
+
+// Bunsetsu is a phrase (文節): one or more content morphemes (自立語) followed
+// by function morphemes (付属語) such as particles and auxiliary verbs, e.g.
+// 本を or 読んでいる. Punctuation is attached to the preceding phrase.
+type Bunsetsu struct {
+	Morphemes []MorphemeInfo
+	Start     int // 文中での最初の形態素の位置
+	Head      int // 主辞（最後の自立語）の Morphemes 中の位置。自立語がなければ -1
+
+	// Predicate is true when the phrase is a predicate: its head is a verb or an
+	// adjective, or a noun or na-adjective followed by an auxiliary such as だ.
+	Predicate bool
+}
+
+// Surface returns the text of the phrase.
+func (b Bunsetsu) Surface() string {
+	var s strings.Builder
+	for _, m := range b.Morphemes {
+		s.WriteString(m.Surface)
+	}
+	return s.String()
+}
+
+// HeadMorpheme returns the head of the phrase. It reports false when the
+// phrase has no content morpheme, e.g. when it consists only of symbols.
+func (b Bunsetsu) HeadMorpheme() (MorphemeInfo, bool) {
+	if b.Head < 0 {
+		return MorphemeInfo{}, false
+	}
+	return b.Morphemes[b.Head], true
+}
+
+// FunctionMorphemes returns the function morphemes after the head, excluding punctuation.
+func (b Bunsetsu) FunctionMorphemes() []MorphemeInfo {
+	var result []MorphemeInfo
+	for _, m := range b.Morphemes[b.Head+1:] {
+		if m.PartOfSpeech != "記号" {
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// Chunk analyses the sentence and groups its morphemes into phrases (文節).
+func (c *Converter) Chunk(sentence string) ([]Bunsetsu, error) {
+	morphemes, err := c.AnalyzeMorphemes(sentence)
+	if err != nil {
+		return nil, err
+	}
+	return chunkMorphemes(morphemes), nil
+}
+
+// chunkMorphemes groups the morphemes into phrases. A phrase starts at a content
+// morpheme that follows a function morpheme or punctuation, or that cannot form
+// a compound with the preceding content morpheme, and at an opening bracket.
+func chunkMorphemes(morphemes []MorphemeInfo) []Bunsetsu {
+	var chunks []Bunsetsu
+	for i, m := range morphemes {
+		if len(chunks) == 0 || startsBunsetsu(chunks[len(chunks)-1], m) {
+			chunks = append(chunks, Bunsetsu{Start: i, Head: -1})
+		}
+		b := &chunks[len(chunks)-1]
+		b.Morphemes = append(b.Morphemes, m)
+		if isContentMorpheme(m) {
+			b.Head = len(b.Morphemes) - 1
+		}
+	}
+
+	for i := range chunks {
+		chunks[i].Predicate = isPredicateBunsetsu(chunks[i])
+	}
+	return chunks
+}
+
+// startsBunsetsu reports whether the morpheme starts a new phrase after b.
+func startsBunsetsu(b Bunsetsu, m MorphemeInfo) bool {
+	last := b.Morphemes[len(b.Morphemes)-1]
+	switch {
+	case m.PartOfSpeech == "記号":
+		return m.PartOfSpeechDetail1 == "括弧開" && last.PartOfSpeechDetail1 != "括弧開"
+	case !isContentMorpheme(m):
+		return false
+	case last.PartOfSpeech == "記号":
+		// The content after an opening bracket belongs to the bracket's phrase
+		return last.PartOfSpeechDetail1 != "括弧開"
+	case !isContentMorpheme(last):
+		return true
+	}
+
+	// Compounds of content morphemes: お茶, 東京タワー, 学生たち, 勉強する
+	switch {
+	case last.PartOfSpeech == "接頭詞":
+		return false
+	case m.PartOfSpeech == "名詞" && m.PartOfSpeechDetail1 == "接尾":
+		return false
+	case m.PartOfSpeech == "名詞" && last.PartOfSpeech == "名詞":
+		return false
+	case m.PartOfSpeech == "動詞" && (m.BaseForm == "する" || m.BaseForm == "できる") &&
+		last.PartOfSpeechDetail1 == "サ変接続":
+		return false
+	}
+	return true
+}
+
+// isContentMorpheme reports whether the morpheme is a content morpheme (自立語)
+// or a prefix, as opposed to a function morpheme (付属語) or punctuation.
+func isContentMorpheme(m MorphemeInfo) bool {
+	switch m.PartOfSpeech {
+	case "助詞", "助動詞", "記号":
+		return false
+	case "動詞", "形容詞":
+		return m.PartOfSpeechDetail1 != "非自立" && m.PartOfSpeechDetail1 != "接尾"
+	case "名詞":
+		// の・ん (読むのは, 読むんだ) and the stems of auxiliaries (ようだ, そうだ)
+		// behave as function morphemes; こと, はず and わけ head their own phrase.
+		if m.PartOfSpeechDetail2 == "助動詞語幹" {
+			return false
+		}
+		return !(m.PartOfSpeechDetail1 == "非自立" && (m.Surface == "の" || m.Surface == "ん"))
+	}
+	return true
+}
+
+// isPredicateBunsetsu reports whether the phrase is a predicate.
+func isPredicateBunsetsu(b Bunsetsu) bool {
+	head, ok := b.HeadMorpheme()
+	if !ok {
+		return false
+	}
+	switch head.PartOfSpeech {
+	case "動詞", "形容詞":
+		return true
+	case "名詞":
+		for _, m := range b.FunctionMorphemes() {
+			if m.PartOfSpeech == "助動詞" {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package kjconv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChunk(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		input      string
+		expected   string // 文節を | で区切ったもの
+		predicates string // 述語の文節
+		heads      string // 各文節の主辞
+	}{
+		{
+			name:       "verb",
+			input:      "彼は本を読んでいる。",
+			expected:   "彼は|本を|読んでいる。",
+			predicates: "読んでいる。",
+			heads:      "彼|本|読ん",
+		},
+		{
+			name:       "compound noun",
+			input:      "東京タワーに行った。",
+			expected:   "東京タワーに|行った。",
+			predicates: "行った。",
+			heads:      "タワー|行っ",
+		},
+		{
+			name:       "noun and copula",
+			input:      "勉強するのは大切なことだ。",
+			expected:   "勉強するのは|大切な|ことだ。",
+			predicates: "勉強するのは|大切な|ことだ。",
+			heads:      "する|大切|こと",
+		},
+		{
+			name:       "clause-internal predicate",
+			input:      "お茶を飲みたいと思う。",
+			expected:   "お茶を|飲みたいと|思う。",
+			predicates: "飲みたいと|思う。",
+			heads:      "お茶|飲み|思う",
+		},
+		{
+			name:       "brackets",
+			input:      "「行く」と言った。",
+			expected:   "「行く」と|言った。",
+			predicates: "「行く」と|言った。",
+			heads:      "行く|言っ",
+		},
+		{
+			name:       "inversion",
+			input:      "行くよ、明日は。",
+			expected:   "行くよ、|明日は。",
+			predicates: "行くよ、",
+			heads:      "行く|明日",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks, err := converter.Chunk(tt.input)
+			if err != nil {
+				t.Fatalf("Chunk() failed: %v", err)
+			}
+
+			var surfaces, predicates, heads []string
+			start := 0
+			for _, b := range chunks {
+				if b.Start != start {
+					t.Errorf("Start of %q = %d, expected %d", b.Surface(), b.Start, start)
+				}
+				start += len(b.Morphemes)
+
+				surfaces = append(surfaces, b.Surface())
+				if b.Predicate {
+					predicates = append(predicates, b.Surface())
+				}
+				if head, ok := b.HeadMorpheme(); ok {
+					heads = append(heads, head.Surface)
+				}
+			}
+
+			if got := strings.Join(surfaces, "|"); got != tt.expected {
+				t.Errorf("Chunk(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+			if got := strings.Join(predicates, "|"); got != tt.predicates {
+				t.Errorf("predicates = %q, expected %q", got, tt.predicates)
+			}
+			if got := strings.Join(heads, "|"); got != tt.heads {
+				t.Errorf("heads = %q, expected %q", got, tt.heads)
+			}
+		})
+	}
+}
+
+func TestBunsetsu_FunctionMorphemes(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	chunks, err := converter.Chunk("読んでいる。")
+	if err != nil {
+		t.Fatalf("Chunk() failed: %v", err)
+	}
+	if len(chunks) != 1 {
+		t.Fatalf("Chunk() returned %d phrases, expected 1", len(chunks))
+	}
+
+	var got []string
+	for _, m := range chunks[0].FunctionMorphemes() {
+		got = append(got, m.Surface)
+	}
+	if strings.Join(got, "|") != "で|いる" {
+		t.Errorf("FunctionMorphemes() = %q, expected [で いる]", got)
+	}
+}
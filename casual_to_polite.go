@@ -5,45 +5,45 @@ import (
 )
 
 // convertCasualToPolite converts a sentence from casual form to polite form.
-func (c *Converter) convertCasualToPolite(sentence string) (string, error) {
+// The IDs of the rules that changed the sentence are added to fired, and the
+// changes are returned as edits against the sentence.
+func (c *Converter) convertCasualToPolite(sentence string, fired *firedRules) (string, []TextEdit, error) {
 	if IsQuotedText(sentence) {
 		// Don't convert quoted text
-		return sentence, nil
+		return sentence, nil, nil
 	}
 	
 	// Handle text with embedded quotes
 	if ContainsQuotedText(sentence) {
-		return ProcessTextWithQuotes(sentence, c.convertCasualToPoliteSegment)
+		return processTextWithQuotes(sentence, func(segment string) (string, []TextEdit, error) {
+			return c.convertCasualToPoliteSegment(segment, fired)
+		})
 	}
 	
-	return c.convertCasualToPoliteSegment(sentence)
+	return c.convertCasualToPoliteSegment(sentence, fired)
 }
 
 // convertCasualToPoliteSegment converts a text segment (without quotes) from casual to polite form.
-func (c *Converter) convertCasualToPoliteSegment(segment string) (string, error) {
+func (c *Converter) convertCasualToPoliteSegment(segment string, fired *firedRules) (string, []TextEdit, error) {
 	if strings.TrimSpace(segment) == "" {
-		return segment, nil
+		return segment, nil, nil
 	}
 	
 	morphemes, err := c.AnalyzeMorphemes(segment)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 	
 	if len(morphemes) == 0 {
-		return segment, nil
+		return segment, nil, nil
 	}
 	
-	// Convert from the end of the sentence
-	converted := c.convertVerbCasualToPolite(morphemes)
-	converted = c.convertAdjectiveCasualToPolite(converted)
-	converted = c.convertNounCasualToPolite(converted)
-	converted = c.convertAuxiliaryCasualToPolite(converted)
-	converted = c.convertConjunctionCasualToPolite(converted)
+	// Apply the conversion rules in order of priority
+	converted := c.applyRules(morphemes, CasualToPolite, fired)
 	
-	result := c.reconstructSentence(converted)
+	result, alignment := c.reconstructSentence(segment, converted)
 	
-	return result, nil
+	return result, alignment, nil
 }
 
 // convertVerbCasualToPolite converts verbs from casual to polite form.
@@ -183,6 +183,11 @@ func (c *Converter) getVerbRenyoukei(morpheme MorphemeInfo) string {
 		return morpheme.Surface
 	}
 	
+	// Literary and classical verbs (文語, ラ変) have no 連用形 that takes ます
+	if isLiteraryInflection(morpheme) {
+		return ""
+	}
+	
 	// Default fallback
 	return strings.TrimSuffix(baseForm, "る")
 }
@@ -251,13 +256,15 @@ func (c *Converter) convertNounCasualToPolite(morphemes []MorphemeInfo) []Morphe
 		if actualLastIdx > 0 {
 			secondLast := result[actualLastIdx-1]
 			if secondLast.Surface == "で" && last.Surface == "ある" {
-				// Replace である with です
+				// Replace である with です, or であります in CopulaDearimasu.
+				// The morphemes carry the features IPADIC gives them, so that
+				// the rules after this one do not take で for the copula だ.
 				result = result[:actualLastIdx-1] // Remove both で and ある
-				result = append(result, MorphemeInfo{
-					Surface: "です",
-					PartOfSpeech: "助動詞",
-					BaseForm: "です",
-				})
+				if c.style.Copula == CopulaDearimasu {
+					result = append(result, auxDa("で", formContinuative), auxAru("あり", formContinuative), auxMasu("ます", formBase))
+				} else {
+					result = append(result, auxDesu("です", formBase))
+				}
 				// Add back any punctuation
 				for i := actualLastIdx + 1; i <= lastIdx; i++ {
 					result = append(result, morphemes[i])
@@ -280,7 +287,6 @@ func (c *Converter) convertAuxiliaryCasualToPolite(morphemes []MorphemeInfo) []M
 	
 	// Handle complex patterns first (multi-morpheme expressions)
 	result = c.handleComplexCasualToPolite(result)
-	result = c.handleNegativeCasualToPolite(result)
 	
 	// Handle single morpheme patterns
 	for i := len(result) - 1; i >= 0; i-- {
@@ -331,16 +337,12 @@ func (c *Converter) handleComplexCasualToPolite(morphemes []MorphemeInfo) []Morp
 			current := result[i]
 			next := result[i+1]
 			
-			// Handle のだ → のです
-			if current.Surface == "の" && (next.Surface == "だ" || next.BaseForm == "だ") {
-				result[i+1].Surface = "です"
-				result[i+1].BaseForm = "です"
-			}
-			
-			// Handle んだ → んです  
-			if current.Surface == "ん" && (next.Surface == "だ" || next.BaseForm == "だ") {
+			// Handle のだ → のです and んだ → んです. Only the copula だ itself
+			// is rewritten; the で of のである and のではない is left alone.
+			if (current.Surface == "の" || current.Surface == "ん") && isCopulaDa(next) {
 				result[i+1].Surface = "です"
 				result[i+1].BaseForm = "です"
+				result[i+1].InflectionType = "特殊・デス"
 			}
 		}
 	}
@@ -348,12 +350,14 @@ func (c *Converter) handleComplexCasualToPolite(morphemes []MorphemeInfo) []Morp
 	// Handle past tense た → ました
 	result = c.handlePastTenseCasualToPolite(result)
 	
-	// Handle negative ない → ません
-	result = c.handleNegativeCasualToPolite(result)
-	
 	return result
 }
 
+// isCopulaDa reports whether m is the copula だ in its base form.
+func isCopulaDa(m MorphemeInfo) bool {
+	return m.PartOfSpeech == "助動詞" && m.Surface == "だ" && m.BaseForm == "だ" && m.InflectionForm == formBase
+}
+
 // handlePastTenseCasualToPolite converts past tense from casual to polite.
 // ～た → ～ました
 func (c *Converter) handlePastTenseCasualToPolite(morphemes []MorphemeInfo) []MorphemeInfo {
@@ -482,47 +486,71 @@ func (c *Converter) handleNegativeCasualToPolite(morphemes []MorphemeInfo) []Mor
 		}
 		
 		// Handle i-adjective negative: ～くない → ～くありません or ～くないです
-		if last.PartOfSpeech == "形容詞" && strings.HasSuffix(last.Surface, "くない") {
-			base := strings.TrimSuffix(last.Surface, "くない")
-			result[actualLastIdx].Surface = base + "くありません"
+		if start, tense, ok := adjectiveNegative(result[:actualLastIdx+1]); ok {
+			p := Predicate{Tense: tense, Polarity: PolarityNegative, Politeness: RegisterPolite}
+			if generated, ok := generateAdjective(result[start], p, c.style); ok {
+				inheritSpans(generated, result[start:actualLastIdx+1])
+				converted := append([]MorphemeInfo{}, result[:start]...)
+				converted = append(converted, generated...)
+				result = append(converted, result[actualLastIdx+1:]...)
+			}
 		}
 	}
 	
 	return result
 }
 
-// reconstructSentence reconstructs a sentence from morphemes.
-func (c *Converter) reconstructSentence(morphemes []MorphemeInfo) string {
+// adjectiveNegative finds a negative i-adjective at the end of morphemes,
+// 美しく|ない or 美しく|なかっ|た, and returns the index of the adjective and
+// the tense.
+func adjectiveNegative(morphemes []MorphemeInfo) (int, Tense, bool) {
+	n := len(morphemes)
+	tense := TenseNonPast
+	if n > 0 && morphemes[n-1].PartOfSpeech == "助動詞" && morphemes[n-1].BaseForm == "た" {
+		tense = TensePast
+		n--
+	}
+	if n < 2 {
+		return 0, tense, false
+	}
+	adjective, nai := morphemes[n-2], morphemes[n-1]
+	if adjective.PartOfSpeech != "形容詞" || adjective.InflectionForm != formTe ||
+		nai.PartOfSpeech != "助動詞" || nai.BaseForm != "ない" {
+		return 0, tense, false
+	}
+	if (tense == TensePast) != (nai.InflectionForm == formTa) {
+		return 0, tense, false
+	}
+	return n - 2, tense, true
+}
+
+// reconstructSentence reconstructs a sentence from the morphemes analysed from
+// source and converted. It also returns the alignment of the sentence with
+// source as edits, one for each morpheme that was changed, inserted or removed.
+func (c *Converter) reconstructSentence(source string, morphemes []MorphemeInfo) (string, []TextEdit) {
 	var parts []string
 	for _, morpheme := range morphemes {
 		parts = append(parts, morpheme.Surface)
 	}
-	return strings.Join(parts, "")
+	return strings.Join(parts, ""), alignMorphemes(source, morphemes)
 }
 // convertConjunctionCasualToPolite converts conjunctions from casual to polite form.
-// だから → ですから, だが → ですが, が → ですが (when used as conjunction)
+// 文頭の接続詞 (だから → ですから, だが → ですが, ...) and
+// が → ですが (when used as conjunction and not already polite)
 func (c *Converter) convertConjunctionCasualToPolite(morphemes []MorphemeInfo) []MorphemeInfo {
 	if len(morphemes) == 0 {
 		return morphemes
 	}
 	
-	result := make([]MorphemeInfo, len(morphemes))
-	copy(result, morphemes)
+	// Convert sentence-initial conjunctions
+	result := convertSentenceInitialConjunction(morphemes, RegisterPolite)
+	result = append([]MorphemeInfo(nil), result...)
 	
-	// Check all morphemes for conjunctions
+	// Check all morphemes for conjunctive particles
 	for i := 0; i < len(result); i++ {
 		morpheme := result[i]
 		
-		// Convert specific conjunctions
 		switch morpheme.Surface {
-		case "だから":
-			if morpheme.PartOfSpeech == "接続詞" {
-				result[i].Surface = "ですから"
-			}
-		case "だが":
-			if morpheme.PartOfSpeech == "接続詞" {
-				result[i].Surface = "ですが"
-			}
 		case "が":
 			// Convert "が" to "ですが" when it's used as a conjunction (接続助詞)
 			// and preceded by "だ" (助動詞)
@@ -538,7 +566,8 @@ func (c *Converter) convertConjunctionCasualToPolite(morphemes []MorphemeInfo) [
 					// Convert "いる" to "います" and keep "が" as "が"
 					result[i-1].Surface = "います"
 					// Keep "が" as is
-				} else {
+				} else if i == 0 || !isPoliteAuxiliary(result[i-1]) {
+					// ますが and ですが are already polite and kept as is
 					result[i].Surface = "ですが"
 				}
 			}
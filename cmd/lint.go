@@ -0,0 +1,129 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ymotongpoo/kjconv"
+)
+
+// runLint runs the lint subcommand, which reports sentences whose register
+// differs from the rest of the text or from the one given with -style. It
+// returns the exit status: 0 when no problems are found, 1 when some are, and
+// 2 on errors.
+//
+//	kjconv lint [-style=auto|polite|casual] [-text=TEXT] [FILE...]
+//
+// Without -text and files the text is read from the standard input.
+func runLint(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("lint", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var (
+		style   = flags.String("style", "auto", "Expected style: 'auto' (the majority), 'polite' or 'casual'")
+		text    = flags.String("text", "", "Text to check instead of files")
+		segment = flags.String("segment", "sentence", "Segmentation: 'sentence' (split at terminators) or 'line' (also split at every line break)")
+		quote   = flags.String("quote", "narration", "Quote policy: 'narration', 'convert' or 'skip', or per bracket, e.g. '「」=convert,『』=skip'")
+		rules   = flags.String("rules", "", "JSON rule file with additional conversion rules")
+	)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: kjconv lint [flags] [FILE...]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	var expected kjconv.Register
+	switch *style {
+	case "auto":
+		expected = kjconv.RegisterUnknown
+	case "polite":
+		expected = kjconv.RegisterPolite
+	case "casual":
+		expected = kjconv.RegisterCasual
+	default:
+		fmt.Fprintf(stderr, "invalid style: %q\n", *style)
+		return 2
+	}
+
+	var opts []kjconv.Option
+	if *rules != "" {
+		opts = append(opts, kjconv.WithRuleFile(*rules))
+	}
+	converter, err := kjconv.NewConverter(opts...)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to create converter: %v\n", err)
+		return 2
+	}
+	switch *segment {
+	case "sentence":
+		converter.SetSegmentation(kjconv.SegmentSentences)
+	case "line":
+		converter.SetSegmentation(kjconv.SegmentLines)
+	default:
+		fmt.Fprintf(stderr, "invalid segment: %q\n", *segment)
+		return 2
+	}
+	if err := setQuotePolicies(converter, *quote); err != nil {
+		fmt.Fprintf(stderr, "invalid quote: %v\n", err)
+		return 2
+	}
+
+	// Each input is a name, empty for -text and the standard input, and a text
+	type input struct{ name, text string }
+	var inputs []input
+	switch {
+	case *text != "":
+		inputs = append(inputs, input{text: *text})
+	case flags.NArg() == 0:
+		b, err := io.ReadAll(stdin)
+		if err != nil {
+			fmt.Fprintf(stderr, "failed to read standard input: %v\n", err)
+			return 2
+		}
+		inputs = append(inputs, input{text: string(b)})
+	default:
+		for _, name := range flags.Args() {
+			b, err := os.ReadFile(name)
+			if err != nil {
+				fmt.Fprintln(stderr, err)
+				return 2
+			}
+			inputs = append(inputs, input{name: name, text: string(b)})
+		}
+	}
+
+	status := 0
+	for _, in := range inputs {
+		issues, err := converter.Lint(in.text, expected)
+		if err != nil {
+			fmt.Fprintf(stderr, "lint failed: %v\n", err)
+			return 2
+		}
+		for _, issue := range issues {
+			fmt.Fprintln(stdout, formatIssue(in.name, issue))
+			status = 1
+		}
+	}
+	return status
+}
+
+// formatIssue formats an issue as "name:line:column: style (expected register): text → suggestion".
+// The name is omitted when it is empty, and "(no dominant style)" is written
+// when the text has no dominant register.
+func formatIssue(name string, issue kjconv.LintIssue) string {
+	expected := fmt.Sprintf("expected %s", issue.Expected)
+	if issue.Expected == kjconv.RegisterUnknown {
+		expected = "no dominant style"
+	}
+	s := fmt.Sprintf("%d:%d: %s (%s): %s", issue.Line, issue.Column, issue.Style, expected, issue.Text)
+	if name != "" {
+		s = name + ":" + s
+	}
+	if issue.Suggestion != "" {
+		s += " → " + issue.Suggestion
+	}
+	return s
+}
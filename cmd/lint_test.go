@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunLint(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  string
+		status int
+		output string
+	}{
+		{
+			name:   "no issues",
+			args:   []string{"-text", "本を読みます。字を書きます。"},
+			status: 0,
+		},
+		{
+			name:   "issues",
+			args:   []string{"-style", "polite", "-text", "本を読みます。字を書く。"},
+			status: 1,
+			output: "1:8: da (expected polite): 字を書く。 → 字を書きます。\n",
+		},
+		{
+			name:   "no dominant style",
+			args:   []string{"-text", "行く。行きます。"},
+			status: 1,
+			output: "1:1: da (no dominant style): 行く。\n1:4: polite (no dominant style): 行きます。\n",
+		},
+		{
+			name:   "standard input",
+			stdin:  "本を読みます。\n字を書く。空が青いです。",
+			status: 1,
+			output: "2:1: da (expected polite): 字を書く。 → 字を書きます。\n",
+		},
+		{
+			name:   "invalid style",
+			args:   []string{"-style", "formal", "-text", "本を読む。"},
+			status: 2,
+		},
+		{
+			name:   "missing file",
+			args:   []string{"testdata/missing.txt"},
+			status: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			status := runLint(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr)
+			if status != tt.status {
+				t.Errorf("runLint() = %d, expected %d (stderr: %q)", status, tt.status, stderr.String())
+			}
+			if stdout.String() != tt.output {
+				t.Errorf("runLint() output = %q, expected %q", stdout.String(), tt.output)
+			}
+		})
+	}
+}
@@ -6,15 +6,25 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 
 	"github.com/ymotongpoo/kjconv"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		os.Exit(runLint(os.Args[2:], os.Stdin, os.Stdout, os.Stderr))
+	}
+
 	var (
-		mode = flag.String("mode", "casual-to-polite", "Conversion mode: 'casual-to-polite' or 'polite-to-casual'")
-		text = flag.String("text", "", "Text to convert")
-		debug = flag.Bool("debug", false, "Enable debug logging")
+		mode    = flag.String("mode", "casual-to-polite", "Conversion mode: 'casual-to-polite', 'polite-to-casual' or 'auto' (unify to the dominant style)")
+		mixed   = flag.Float64("mixed-threshold", kjconv.DefaultMixedThreshold, "With -mode=auto, the share of the dominant style below which the text is rejected as mixed")
+		text    = flag.String("text", "", "Text to convert")
+		segment = flag.String("segment", "sentence", "Segmentation: 'sentence' (split at terminators) or 'line' (also split at every line break)")
+		quote   = flag.String("quote", "narration", "Quote policy: 'narration', 'convert' or 'skip', or per bracket, e.g. '「」=convert,『』=skip'")
+		rules   = flag.String("rules", "", "JSON rule file with additional conversion rules")
+		onError = flag.String("on-error", "abort", "On a sentence that cannot be converted: 'abort' or 'continue' (leave it unchanged and exit with 1)")
+		debug   = flag.Bool("debug", false, "Enable debug logging")
 	)
 	flag.Parse()
 
@@ -35,7 +45,11 @@ func main() {
 
 	slog.Debug("starting conversion", "input", *text, "mode", *mode)
 
-	converter, err := kjconv.NewConverter()
+	opts := []kjconv.Option{kjconv.WithLogger(logger)}
+	if *rules != "" {
+		opts = append(opts, kjconv.WithRuleFile(*rules))
+	}
+	converter, err := kjconv.NewConverter(opts...)
 	if err != nil {
 		slog.Error("failed to create converter", "error", err)
 		os.Exit(1)
@@ -47,17 +61,82 @@ func main() {
 		convMode = kjconv.CasualToPolite
 	case "polite-to-casual":
 		convMode = kjconv.PoliteToCasual
+	case "auto":
+		convMode = kjconv.AutoUnify
 	default:
 		slog.Error("invalid mode", "mode", *mode)
 		os.Exit(1)
 	}
 
-	result, err := converter.Convert(*text, convMode)
+	switch *segment {
+	case "sentence":
+		converter.SetSegmentation(kjconv.SegmentSentences)
+	case "line":
+		converter.SetSegmentation(kjconv.SegmentLines)
+	default:
+		slog.Error("invalid segment", "segment", *segment)
+		os.Exit(1)
+	}
+
+	if err := setQuotePolicies(converter, *quote); err != nil {
+		slog.Error("invalid quote", "quote", *quote, "error", err)
+		os.Exit(1)
+	}
+
+	errorPolicy, err := kjconv.ParseErrorPolicy(*onError)
 	if err != nil {
+		slog.Error("invalid on-error", "on-error", *onError, "error", err)
+		os.Exit(1)
+	}
+	converter.SetErrorPolicy(errorPolicy)
+
+	if err := converter.SetMixedThreshold(*mixed); err != nil {
+		slog.Error("invalid mixed threshold", "mixed-threshold", *mixed, "error", err)
+		os.Exit(1)
+	}
+
+	result, err := converter.ConvertDetailed(*text, convMode)
+	if result == nil {
 		slog.Error("conversion failed", "error", err)
 		os.Exit(1)
 	}
 
-	slog.Debug("conversion completed", "output", result)
-	fmt.Println(result)
+	for _, w := range result.Warnings {
+		slog.Warn("sentence not converted", "index", w.SentenceIndex, "reason", w.Reason.String(), "text", w.Text)
+	}
+
+	slog.Debug("conversion completed", "output", result.Text)
+	fmt.Println(result.Text)
+
+	// Under -on-error=continue the output is partial
+	if err != nil {
+		slog.Error("some sentences were not converted", "error", err)
+		os.Exit(1)
+	}
+}
+
+// setQuotePolicies parses the -quote flag and sets the quote policies of the converter.
+// The value is either a single policy for all quotations or a comma-separated
+// list of bracket=policy pairs.
+func setQuotePolicies(converter *kjconv.Converter, value string) error {
+	for _, item := range strings.Split(value, ",") {
+		brackets, name, found := strings.Cut(strings.TrimSpace(item), "=")
+		if !found {
+			brackets, name = "", brackets
+		}
+		policy, err := kjconv.ParseQuotePolicy(name)
+		if err != nil {
+			return err
+		}
+
+		// Only the opening bracket identifies the quotation type
+		var open []rune
+		if r := []rune(brackets); len(r) > 0 {
+			open = r[:1]
+		}
+		if err := converter.SetQuotePolicy(policy, open...); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,163 @@
+package kjconv
+
+import (
+	"strings"
+)
+
+// Inflection forms produced by conjugate, named as in IPADIC.
+const (
+	formBase         = "基本形"   // 読む, 美しい
+	formIrrealis     = "未然形"   // 読ま(ない)
+	formVolitional   = "未然ウ接続" // 読も(う), 美しかろ(う)
+	formContinuative = "連用形"   // 読み(ます)
+	formTa           = "連用タ接続" // 読ん(だ), 美しかっ(た)
+	formTe           = "連用テ接続" // 美しく(ない)
+)
+
+// godanEndings maps the inflection type of a godan verb to the endings of its
+// forms: 未然形, 未然ウ接続, 連用形, 連用タ接続 and 基本形.
+var godanEndings = map[string][5]string{
+	"五段・カ行イ音便":   {"か", "こ", "き", "い", "く"},
+	"五段・カ行促音便":   {"か", "こ", "き", "っ", "く"},
+	"五段・カ行促音便ユク": {"か", "こ", "き", "っ", "く"},
+	"五段・ガ行":      {"が", "ご", "ぎ", "い", "ぐ"},
+	"五段・サ行":      {"さ", "そ", "し", "し", "す"},
+	"五段・タ行":      {"た", "と", "ち", "っ", "つ"},
+	"五段・ナ行":      {"な", "の", "に", "ん", "ぬ"},
+	"五段・バ行":      {"ば", "ぼ", "び", "ん", "ぶ"},
+	"五段・マ行":      {"ま", "も", "み", "ん", "む"},
+	"五段・ラ行":      {"ら", "ろ", "り", "っ", "る"},
+	"五段・ラ行特殊":    {"ら", "ろ", "い", "っ", "る"},
+	"五段・ラ行アル":    {"ら", "ろ", "り", "っ", "る"},
+	"五段・ワ行促音便":   {"わ", "お", "い", "っ", "う"},
+	"五段・ワ行ウ音便":   {"わ", "お", "い", "う", "う"},
+}
+
+// godanForms lists the forms in the order of the endings in godanEndings.
+var godanForms = [5]string{formIrrealis, formVolitional, formContinuative, formTa, formBase}
+
+// conjugate returns the verb or adjective m in the given form. The auxiliaries
+// たい and ある (of である) are conjugated like an adjective and a godan verb.
+// It reports false when the inflection type of m is not supported.
+func conjugate(m MorphemeInfo, form string) (MorphemeInfo, bool) {
+	base := m.BaseForm
+	var surface string
+	switch {
+	case m.PartOfSpeech == "形容詞" || m.InflectionType == "特殊・タイ":
+		stem, ok := strings.CutSuffix(base, "い")
+		if !ok {
+			return m, false
+		}
+		if base == "いい" {
+			// いい conjugates as よい: よく, よかった
+			stem = "よ"
+		}
+		switch form {
+		case formBase:
+			surface = base
+		case formTe:
+			surface = stem + "く"
+		case formTa:
+			surface = stem + "かっ"
+		case formVolitional:
+			surface = stem + "かろ"
+		default:
+			return m, false
+		}
+
+	case m.PartOfSpeech != "動詞" && m.InflectionType != "五段・ラ行アル":
+		return m, false
+
+	case strings.HasPrefix(m.InflectionType, "五段"):
+		endings, ok := godanEndings[m.InflectionType]
+		if !ok {
+			return m, false
+		}
+		stem, ok := strings.CutSuffix(base, endings[4])
+		if !ok {
+			return m, false
+		}
+		for i, f := range godanForms {
+			if f == form {
+				surface = stem + endings[i]
+			}
+		}
+
+	case strings.HasPrefix(m.InflectionType, "一段"):
+		stem, ok := strings.CutSuffix(base, "る")
+		if !ok {
+			return m, false
+		}
+		switch form {
+		case formBase:
+			surface = base
+		case formVolitional:
+			surface = stem + "よ"
+		default:
+			surface = stem
+		}
+
+	case m.InflectionType == "カ変・来ル" || m.InflectionType == "カ変・クル":
+		stem, ok := strings.CutSuffix(base, "る")
+		if !ok {
+			return m, false
+		}
+		switch {
+		case form == formBase:
+			surface = base
+		case stem == "来" && form == formVolitional:
+			surface = "来よ"
+		case stem == "来":
+			surface = "来"
+		case form == formIrrealis:
+			surface = "こ"
+		case form == formVolitional:
+			surface = "こよ"
+		default:
+			surface = "き"
+		}
+
+	case m.InflectionType == "サ変・スル" || m.InflectionType == "サ変・−スル":
+		stem, ok := strings.CutSuffix(base, "する")
+		if !ok {
+			return m, false
+		}
+		switch form {
+		case formBase:
+			surface = base
+		case formVolitional:
+			surface = stem + "しよ"
+		default:
+			surface = stem + "し"
+		}
+
+	default:
+		return m, false
+	}
+
+	if surface == "" {
+		return m, false
+	}
+	m.Surface = surface
+	m.InflectionForm = form
+	return m, true
+}
+
+// pastForm returns the form that takes the past auxiliary た and the particle て:
+// 連用タ接続 for godan verbs and adjectives, and 連用形 for the others.
+func pastForm(m MorphemeInfo) string {
+	if m.PartOfSpeech == "形容詞" || strings.HasPrefix(m.InflectionType, "五段") {
+		return formTa
+	}
+	return formContinuative
+}
+
+// isVoicedTa reports whether the past auxiliary and the particle て become だ and
+// で after the verb in 連用タ接続, as in 読んだ and 泳いだ.
+func isVoicedTa(m MorphemeInfo) bool {
+	switch m.InflectionType {
+	case "五段・ガ行", "五段・ナ行", "五段・バ行", "五段・マ行":
+		return m.InflectionForm == formTa
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package kjconv
+
+import (
+	"strings"
+)
+
+// sentenceInitialConjunctions is the table of copula-bearing connectives that are
+// converted when they appear at the start of a sentence.
+var sentenceInitialConjunctions = []struct {
+	casual string // 常体
+	polite string // 敬体
+}{
+	{casual: "だから", polite: "ですから"},
+	{casual: "だが", polite: "ですが"},
+	{casual: "だけど", polite: "ですけど"},
+	{casual: "だったら", polite: "でしたら"},
+	{casual: "だとすれば", polite: "ですとすれば"},
+	{casual: "なので", polite: "ですので"},
+	{casual: "それだから", polite: "それですから"},
+}
+
+// convertSentenceInitialConjunction converts the connective at the start of a
+// sentence using sentenceInitialConjunctions. The connective may span several
+// morphemes (e.g. です + から) and must be followed by the rest of the sentence,
+// so that only connectives in 接続詞 position are converted.
+func convertSentenceInitialConjunction(morphemes []MorphemeInfo, target Register) []MorphemeInfo {
+	for _, entry := range sentenceInitialConjunctions {
+		from, to := entry.casual, entry.polite
+		if target == RegisterCasual {
+			from, to = entry.polite, entry.casual
+		}
+
+		n := matchLeadingSurface(morphemes, from)
+		if n == 0 || n >= len(morphemes) {
+			continue
+		}
+		// The connective must not be followed by a particle or auxiliary
+		// (e.g. なのである is not a connective).
+		if next := morphemes[n]; next.PartOfSpeech == "助詞" || next.PartOfSpeech == "助動詞" {
+			continue
+		}
+
+		result := make([]MorphemeInfo, 0, len(morphemes)-n+1)
+		result = append(result, MorphemeInfo{
+			Surface:      to,
+			PartOfSpeech: "接続詞",
+			BaseForm:     to,
+		})
+		return append(result, morphemes[n:]...)
+	}
+
+	return morphemes
+}
+
+// matchLeadingSurface returns the number of leading morphemes whose surfaces
+// concatenate to s, or 0 if s does not end at a morpheme boundary.
+func matchLeadingSurface(morphemes []MorphemeInfo, s string) int {
+	var b strings.Builder
+	for i, m := range morphemes {
+		b.WriteString(m.Surface)
+		prefix := b.String()
+		if prefix == s {
+			return i + 1
+		}
+		if !strings.HasPrefix(s, prefix) {
+			return 0
+		}
+	}
+	return 0
+}
@@ -40,6 +40,36 @@ func TestConjunctionConversion_CasualToPolite(t *testing.T) {
 			input:    "だから今日は晴れだ。明日は雨だ。",
 			expected: "ですから今日は晴れです。明日は雨です。",
 		},
+		{
+			name:     "だけど → ですけど",
+			input:    "だけど今日は晴れだ。",
+			expected: "ですけど今日は晴れです。",
+		},
+		{
+			name:     "だったら → でしたら",
+			input:    "だったら本を読む。",
+			expected: "でしたら本を読みます。",
+		},
+		{
+			name:     "だとすれば → ですとすれば",
+			input:    "だとすれば本を読む。",
+			expected: "ですとすれば本を読みます。",
+		},
+		{
+			name:     "なので → ですので",
+			input:    "なので本を読む。",
+			expected: "ですので本を読みます。",
+		},
+		{
+			name:     "それだから → それですから",
+			input:    "それだから本を読む。",
+			expected: "それですから本を読みます。",
+		},
+		{
+			name:     "文中のなのでは変換しない",
+			input:    "今日は晴れなので本を読む。",
+			expected: "今日は晴れなので本を読みます。",
+		},
 		{
 			name:     "文中の「が」接続詞変換",
 			input:    "すべてのクライアントがローカルの場合、エンドポイントをaaaにバインドするのが望ましいが、この例の構成では便宜上「未指定」アドレスbbbを使用している。",
@@ -102,6 +132,31 @@ func TestConjunctionConversion_PoliteToCasual(t *testing.T) {
 			input:    "ですから今日は晴れです。明日は雨です。",
 			expected: "だから今日は晴れだ。明日は雨だ。",
 		},
+		{
+			name:     "ですけど → だけど",
+			input:    "ですけど今日は晴れです。",
+			expected: "だけど今日は晴れだ。",
+		},
+		{
+			name:     "でしたら → だったら",
+			input:    "でしたら本を読みます。",
+			expected: "だったら本を読む。",
+		},
+		{
+			name:     "ですとすれば → だとすれば",
+			input:    "ですとすれば本を読みます。",
+			expected: "だとすれば本を読む。",
+		},
+		{
+			name:     "ですので → なので",
+			input:    "ですので本を読みます。",
+			expected: "なので本を読む。",
+		},
+		{
+			name:     "それですから → それだから",
+			input:    "それですから本を読みます。",
+			expected: "それだから本を読む。",
+		},
 		{
 			name:     "文中の「ですが」接続詞変換",
 			input:    "すべてのクライアントがローカルの場合、エンドポイントをaaaにバインドするのが望ましいですが、この例の構成では便宜上「未指定」アドレスbbbを使用しています。",
@@ -0,0 +1,226 @@
+package kjconv
+
+// WritingStyle is the style of a sentence as classified by DetectStyle.
+type WritingStyle int
+
+const (
+	// StyleUnknown is used when the style cannot be determined, e.g. for a
+	// quotation or a sentence without a predicate.
+	StyleUnknown WritingStyle = iota
+	// StylePolite is the polite style (です・ます調).
+	StylePolite
+	// StyleDa is the plain style (だ調), including sentences ending with a plain
+	// verb or adjective.
+	StyleDa
+	// StyleDearu is the written plain style (である調).
+	StyleDearu
+	// StyleNounEnding is a sentence ending with a noun (体言止め), which fits
+	// any style.
+	StyleNounEnding
+)
+
+// String returns a human readable name of the style.
+func (s WritingStyle) String() string {
+	switch s {
+	case StylePolite:
+		return "polite"
+	case StyleDa:
+		return "da"
+	case StyleDearu:
+		return "dearu"
+	case StyleNounEnding:
+		return "noun-ending"
+	default:
+		return "unknown"
+	}
+}
+
+// Register returns the register of the style: RegisterPolite for StylePolite,
+// RegisterCasual for StyleDa and StyleDearu, and RegisterUnknown otherwise.
+func (s WritingStyle) Register() Register {
+	switch s {
+	case StylePolite:
+		return RegisterPolite
+	case StyleDa, StyleDearu:
+		return RegisterCasual
+	default:
+		return RegisterUnknown
+	}
+}
+
+// StyleReport is the result of DetectStyle.
+type StyleReport struct {
+	Sentences []SentenceStyle      // 文ごとの文体
+	Counts    map[WritingStyle]int // 文体ごとの文の数
+	Dominant  WritingStyle         // 最も多い文体
+}
+
+// SentenceStyle is the style of a single sentence.
+type SentenceStyle struct {
+	Text  string       // 文
+	Input Range        // 入力中の位置
+	Style WritingStyle // 文体
+
+	// Confidence is between 0 and 1. It is lowered for sentences whose style
+	// is less telling, such as headings, sentences with final particles (行くよね)
+	// and inverted sentences.
+	Confidence float64
+}
+
+// DetectStyle classifies the style of each sentence of the text without
+// converting it. Sentences are split and analysed as in Convert, so quotations
+// and protected spans do not affect the style of the sentence around them.
+//
+// The dominant style is the most frequent of StylePolite, StyleDa and
+// StyleDearu; StyleNounEnding and StyleUnknown fit any style and are not
+// counted. It is StyleUnknown when there is a tie or no sentence has one of
+// these styles.
+func (c *Converter) DetectStyle(text string) (*StyleReport, error) {
+	return c.detectDocumentStyle(c.ParseDocument(text))
+}
+
+// detectDocumentStyle classifies the style of each sentence of the document.
+func (c *Converter) detectDocumentStyle(doc *Document) (*StyleReport, error) {
+	report := &StyleReport{Counts: make(map[WritingStyle]int)}
+	input := newRuneCounter(doc.Render())
+	for _, p := range doc.Paragraphs {
+		for _, s := range p.Sentences {
+			if len(s.Spans) == 0 {
+				continue
+			}
+			masked, _ := maskSpans(s.Spans)
+			style, confidence, err := c.detectSentenceStyle(masked)
+			if err != nil {
+				return nil, err
+			}
+
+			sentence := s.Text()
+			report.Sentences = append(report.Sentences, SentenceStyle{
+				Text:       sentence,
+				Input:      input.rangeOf(s.Offset, s.Offset+len(sentence)),
+				Style:      style,
+				Confidence: confidence,
+			})
+			report.Counts[style]++
+		}
+	}
+	report.Dominant = dominantStyle(report.Counts)
+	return report, nil
+}
+
+// detectSentenceStyle classifies the style of a sentence.
+func (c *Converter) detectSentenceStyle(sentence string) (WritingStyle, float64, error) {
+	if IsQuotedText(sentence) {
+		return StyleUnknown, 0, nil
+	}
+	morphemes, err := c.AnalyzeMorphemes(sentence)
+	if err != nil {
+		return StyleUnknown, 0, err
+	}
+	if !hasContent(morphemes) {
+		return StyleUnknown, 0, nil
+	}
+
+	confidence := 1.0
+	if isHeadingLike(sentence) {
+		confidence -= 0.2
+	}
+
+	p := analyzePredicate(morphemes)
+	if p == nil {
+		if isNounEnding(morphemes) {
+			return StyleNounEnding, confidence, nil
+		}
+		switch detectRegister(morphemes) {
+		case RegisterPolite:
+			return StylePolite, confidence / 2, nil
+		case RegisterCasual:
+			return StyleDa, confidence / 2, nil
+		}
+		return StyleUnknown, 0, nil
+	}
+
+	if last := morphemes[p.End-1]; last.PartOfSpeech == "助詞" && last.PartOfSpeechDetail1 == "接続助詞" {
+		// A sentence ending with a clause, e.g. 雨が降ったので。
+		confidence /= 2
+	}
+	for _, m := range morphemes[p.End:] {
+		if isSentenceFinalParticle(m) {
+			// 行くよね and 行くわ are as much speech as style
+			confidence -= 0.2
+			break
+		}
+	}
+	if isInverted(morphemes) {
+		confidence -= 0.2
+	}
+
+	switch {
+	case p.Politeness == RegisterPolite:
+		return StylePolite, confidence, nil
+	case isDearu(morphemes[p.HeadIndex:p.End]):
+		return StyleDearu, confidence, nil
+	}
+	return StyleDa, confidence, nil
+}
+
+// isDearu reports whether the predicate ends with the copula である (である,
+// であった, であろう).
+func isDearu(predicate []MorphemeInfo) bool {
+	for i := 1; i < len(predicate); i++ {
+		m := predicate[i]
+		if m.PartOfSpeech == "助動詞" && m.BaseForm == "ある" && predicate[i-1].Surface == "で" {
+			return true
+		}
+	}
+	return false
+}
+
+// dominantStyle returns the most frequent of the polite and plain styles.
+func dominantStyle(counts map[WritingStyle]int) WritingStyle {
+	dominant, most, tie := StyleUnknown, 0, false
+	for _, s := range []WritingStyle{StylePolite, StyleDa, StyleDearu} {
+		switch n := counts[s]; {
+		case n > most:
+			dominant, most, tie = s, n, false
+		case n == most && n > 0:
+			tie = true
+		}
+	}
+	if tie {
+		return StyleUnknown
+	}
+	return dominant
+}
+
+// DominantRegister returns the register of most sentences, counting StylePolite
+// as polite and StyleDa and StyleDearu as casual. It is RegisterUnknown when
+// there is a tie.
+func (r *StyleReport) DominantRegister() Register {
+	polite := r.Counts[StylePolite]
+	casual := r.Counts[StyleDa] + r.Counts[StyleDearu]
+	switch {
+	case polite > casual:
+		return RegisterPolite
+	case casual > polite:
+		return RegisterCasual
+	default:
+		return RegisterUnknown
+	}
+}
+
+// IsMixed reports whether the document mixes registers: whether the share of
+// the dominant register among the sentences with a register is below the
+// threshold (between 0 and 1). A tie is always mixed, and a document without
+// polite or casual sentences is never mixed.
+func (r *StyleReport) IsMixed(threshold float64) bool {
+	polite := r.Counts[StylePolite]
+	casual := r.Counts[StyleDa] + r.Counts[StyleDearu]
+	if polite+casual == 0 {
+		return false
+	}
+	if polite == casual {
+		return true
+	}
+	return float64(max(polite, casual))/float64(polite+casual) < threshold
+}
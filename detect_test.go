@@ -0,0 +1,108 @@
+package kjconv
+
+import (
+	"testing"
+)
+
+func TestDetectStyle(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		input      string
+		style      WritingStyle
+		confidence float64
+	}{
+		{"本を読みます。", StylePolite, 1},
+		{"今日は晴れでした。", StylePolite, 1},
+		{"本を読んでください。", StylePolite, 1},
+		{"お読みください。", StylePolite, 1},
+		{"本を読む。", StyleDa, 1},
+		{"今日は晴れだ。", StyleDa, 1},
+		{"この花は美しい。", StyleDa, 1},
+		{"本書は入門書である。", StyleDearu, 1},
+		{"これは例であった。", StyleDearu, 1},
+		{"美しい花。", StyleNounEnding, 1},
+		{"行くよね。", StyleDa, 0.8},
+		{"行くよ、明日は。", StyleDa, 0.6},
+		{"雨が降ったので。", StyleDa, 0.5},
+		{"- 本を読む", StyleDa, 0.8},
+		{"「雨だ」", StyleUnknown, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			report, err := converter.DetectStyle(tt.input)
+			if err != nil {
+				t.Fatalf("DetectStyle() failed: %v", err)
+			}
+			if len(report.Sentences) != 1 {
+				t.Fatalf("DetectStyle() returned %d sentences, expected 1", len(report.Sentences))
+			}
+			s := report.Sentences[0]
+			if s.Style != tt.style || s.Confidence < tt.confidence-0.01 || s.Confidence > tt.confidence+0.01 {
+				t.Errorf("DetectStyle(%q) = %v (%.2f), expected %v (%.2f)", tt.input, s.Style, s.Confidence, tt.style, tt.confidence)
+			}
+		})
+	}
+}
+
+func TestDetectStyle_Document(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	input := "本を読みます。「雨だ」と言いました。\n\n美しい花。字を書く。空が青いです。"
+	report, err := converter.DetectStyle(input)
+	if err != nil {
+		t.Fatalf("DetectStyle() failed: %v", err)
+	}
+
+	expected := map[WritingStyle]int{StylePolite: 3, StyleDa: 1, StyleNounEnding: 1}
+	for style, n := range expected {
+		if report.Counts[style] != n {
+			t.Errorf("Counts[%v] = %d, expected %d", style, report.Counts[style], n)
+		}
+	}
+	if report.Dominant != StylePolite {
+		t.Errorf("Dominant = %v, expected %v", report.Dominant, StylePolite)
+	}
+	for _, s := range report.Sentences {
+		if got := input[s.Input.Start:s.Input.End]; got != s.Text {
+			t.Errorf("input[%d:%d] = %q, expected %q", s.Input.Start, s.Input.End, got, s.Text)
+		}
+	}
+
+	// A tie has no dominant style
+	report, err = converter.DetectStyle("本を読みます。字を書く。")
+	if err != nil {
+		t.Fatalf("DetectStyle() failed: %v", err)
+	}
+	if report.Dominant != StyleUnknown {
+		t.Errorf("Dominant = %v, expected %v", report.Dominant, StyleUnknown)
+	}
+}
+
+func TestStyleReport_IsMixed(t *testing.T) {
+	tests := []struct {
+		counts    map[WritingStyle]int
+		threshold float64
+		expected  bool
+	}{
+		{map[WritingStyle]int{StylePolite: 3, StyleDa: 1}, 0.6, false},
+		{map[WritingStyle]int{StylePolite: 3, StyleDa: 1, StyleDearu: 1}, 0.6, false},
+		{map[WritingStyle]int{StylePolite: 3, StyleDa: 1, StyleDearu: 1}, 0.7, true},
+		{map[WritingStyle]int{StylePolite: 2, StyleDa: 2}, 0, true},
+		{map[WritingStyle]int{StyleNounEnding: 2}, 0.6, false},
+	}
+
+	for _, tt := range tests {
+		report := &StyleReport{Counts: tt.counts}
+		if got := report.IsMixed(tt.threshold); got != tt.expected {
+			t.Errorf("IsMixed(%v) with %v = %v, expected %v", tt.threshold, tt.counts, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,426 @@
+package kjconv
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// SpanKind identifies the kind of a span in a sentence.
+type SpanKind int
+
+const (
+	// SpanText is ordinary text that may be converted.
+	SpanText SpanKind = iota
+	// SpanQuote is a quotation in 「」 or 『』 including its brackets.
+	// How it is converted depends on the QuotePolicy.
+	SpanQuote
+	// SpanProtected is text that is never converted, such as a URL or inline
+	// code. It is analysed as a single noun.
+	SpanProtected
+	// SpanMarkup is formatting that is not part of the sentence, such as an HTML
+	// tag or Markdown emphasis. It is never converted and is analysed as a symbol.
+	SpanMarkup
+)
+
+// String returns a human readable name of the kind.
+func (k SpanKind) String() string {
+	switch k {
+	case SpanText:
+		return "text"
+	case SpanQuote:
+		return "quote"
+	case SpanProtected:
+		return "protected"
+	case SpanMarkup:
+		return "markup"
+	default:
+		return "unknown"
+	}
+}
+
+// Span is a piece of a sentence.
+type Span struct {
+	Kind   SpanKind
+	Text   string // 表示される文字列（SpanQuote では括弧を含む）
+	Offset int    // 入力中の開始位置（バイト）
+	Spans  []Span // SpanQuote の括弧の内側
+}
+
+// end returns the byte offset just after the span in the input.
+func (s Span) end() int {
+	return s.Offset + len(s.Text)
+}
+
+// Sentence is a sentence and the whitespace around it.
+type Sentence struct {
+	Leading  string // 文の直前の空白
+	Spans    []Span // 文を構成する要素
+	Trailing string // 文の直後の空白・改行
+	Offset   int    // 入力中の文の開始位置（バイト）
+}
+
+// Text returns the sentence without the whitespace around it.
+func (s Sentence) Text() string {
+	return renderSpans(s.Spans)
+}
+
+// Paragraph is a run of sentences separated from the next paragraph by blank lines.
+type Paragraph struct {
+	Sentences []Sentence
+	Offset    int // 入力中の段落の開始位置（バイト）
+}
+
+// Document is a text divided into paragraphs, sentences and spans.
+// Format adapters can build a Document from their own syntax, marking
+// formatting as SpanMarkup, and render the converted Document back.
+type Document struct {
+	Paragraphs []Paragraph
+}
+
+// Render returns the text of the document. Rendering a document returned by
+// ParseDocument reproduces the input byte-for-byte.
+func (d *Document) Render() string {
+	var b strings.Builder
+	for _, p := range d.Paragraphs {
+		for _, s := range p.Sentences {
+			b.WriteString(s.Leading)
+			b.WriteString(s.Text())
+			b.WriteString(s.Trailing)
+		}
+	}
+	return b.String()
+}
+
+// renderSpans concatenates the text of the spans.
+func renderSpans(spans []Span) string {
+	var b strings.Builder
+	for _, s := range spans {
+		b.WriteString(s.Text)
+	}
+	return b.String()
+}
+
+// ParseDocument parses plain text into a Document using DefaultTerminators and
+// DefaultProtectedPatterns.
+func ParseDocument(text string) *Document {
+	return parseDocument(text, DefaultProtectedPatterns(), DefaultTerminators().SplitSegments)
+}
+
+// ParseDocument parses plain text into a Document using the converter's
+// terminators, segmentation and protected patterns.
+func (c *Converter) ParseDocument(text string) *Document {
+	return parseDocument(text, c.protectedPatterns, c.splitSegments)
+}
+
+// parseDocument splits text into paragraphs and sentences with split, and each
+// sentence into spans. Protected spans are found before splitting, so that a
+// sentence is never split inside a URL or a file path.
+func parseDocument(text string, patterns []*regexp.Regexp, split func(string) []Segment) *Document {
+	masked, m := protectSpans(text, patterns)
+
+	doc := &Document{}
+	offset := 0 // 入力中の位置
+	for _, piece := range splitParagraphs(masked) {
+		p := Paragraph{Offset: offset}
+		for _, segment := range split(piece) {
+			s := Sentence{
+				Leading:  segment.Leading,
+				Trailing: segment.Trailing,
+				Offset:   offset + len(segment.Leading),
+			}
+			s.Spans = buildSpans(segment.Text, m, s.Offset)
+			offset = s.Offset + len(s.Text()) + len(s.Trailing)
+			p.Sentences = append(p.Sentences, s)
+		}
+		doc.Paragraphs = append(doc.Paragraphs, p)
+	}
+	return doc
+}
+
+// splitParagraphs splits text after each run of blank lines that follows a
+// non-blank line. Blank lines inside brackets do not end a paragraph.
+func splitParagraphs(text string) []string {
+	brackets := outermostSpans(scanBrackets(text), func(bracketSpan) bool { return true })
+
+	var pieces []string
+	start := 0
+	blank := false   // 直前の行が空行か
+	content := false // 段落に空行以外の行があるか
+	for lineStart := 0; lineStart < len(text); {
+		lineEnd := len(text)
+		if i := strings.IndexByte(text[lineStart:], '\n'); i >= 0 {
+			lineEnd = lineStart + i + 1
+		}
+		line := text[lineStart:lineEnd]
+
+		isBlank := strings.TrimFunc(line, unicode.IsSpace) == ""
+		inBracket := false
+		for _, b := range brackets {
+			if b.contains(lineStart) {
+				inBracket = true
+				break
+			}
+		}
+		if !isBlank && blank && content && !inBracket {
+			pieces = append(pieces, text[start:lineStart])
+			start = lineStart
+			content = false
+		}
+		blank = isBlank
+		content = content || !isBlank
+		lineStart = lineEnd
+	}
+	if start < len(text) || len(pieces) == 0 {
+		pieces = append(pieces, text[start:])
+	}
+	return pieces
+}
+
+// buildSpans divides the sentence text, in which protected spans and markup are
+// replaced by the placeholders of m, into spans. offset is the position of text
+// in the input; offsets of the following spans are counted from it and from
+// the end of each placeholder's span.
+func buildSpans(text string, m *spanMask, offset int) []Span {
+	var spans []Span
+	quotes := outermostSpans(scanBrackets(text), bracketSpan.isQuote)
+	start := 0 // まだ追加していないテキストの開始位置
+
+	flush := func(end int) {
+		if end > start {
+			spans = append(spans, Span{Kind: SpanText, Text: text[start:end], Offset: offset})
+			offset += end - start
+		}
+		start = end
+	}
+
+	for i := 0; i < len(text); {
+		if len(quotes) > 0 && i == quotes[0].Start {
+			flush(i)
+			q := quotes[0]
+			quotes = quotes[1:]
+
+			open := utf8.RuneLen(q.Open)
+			closing := q.End - utf8.RuneLen(bracketPairs[q.Open])
+			inner := buildSpans(text[i+open:closing], m, offset+open)
+			quote := Span{
+				Kind:   SpanQuote,
+				Text:   text[i:i+open] + renderSpans(inner) + text[closing:q.End],
+				Offset: offset,
+				Spans:  inner,
+			}
+			spans = append(spans, quote)
+			offset = quote.end()
+			i, start = q.End, q.End
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if span, ok := m.spans[r]; ok {
+			flush(i)
+			spans = append(spans, span)
+			offset = span.end()
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	flush(len(text))
+	return spans
+}
+
+// maskSpans returns the text of the spans with protected spans and markup
+// replaced by placeholders, for conversion.
+func maskSpans(spans []Span) (string, *spanMask) {
+	m := newSpanMask(renderSpans(spans))
+	var b strings.Builder
+	writeMasked(&b, spans, m)
+	return b.String(), m
+}
+
+// writeMasked writes the masked text of the spans to b.
+func writeMasked(b *strings.Builder, spans []Span, m *spanMask) {
+	for _, s := range spans {
+		switch s.Kind {
+		case SpanProtected, SpanMarkup:
+			if r, ok := m.add(s); ok {
+				b.WriteRune(r)
+			} else {
+				b.WriteString(s.Text)
+			}
+		case SpanQuote:
+			if s.Spans == nil {
+				b.WriteString(s.Text)
+				continue
+			}
+			// Keep the brackets around the masked content
+			open, _ := utf8.DecodeRuneInString(s.Text)
+			closing, _ := utf8.DecodeLastRuneInString(s.Text)
+			b.WriteRune(open)
+			writeMasked(b, s.Spans, m)
+			b.WriteRune(closing)
+		default:
+			b.WriteString(s.Text)
+		}
+	}
+}
+
+// ConvertDocument converts the sentences of the document in place and reports
+// the result of each sentence. The spans of a converted sentence are rebuilt
+// from the converted text; protected spans and markup keep their offsets, and
+// other spans are placed relative to them.
+//
+// A sentence that cannot be converted, or whose conversion panics, is reported
+// as a *SentenceError. Under ErrorAbort (the default) the conversion stops there
+// and the document is left partly converted; under ErrorContinue the sentence
+// is left unchanged and the result is returned with the errors of all such
+// sentences joined. An undefined mode is rejected with ErrUnsupportedMode.
+func (c *Converter) ConvertDocument(doc *Document, mode ConversionMode) (*Result, error) {
+	if !validMode(mode) {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedMode, mode)
+	}
+	if mode == AutoUnify {
+		var err error
+		if mode, err = c.unifyingMode(doc); err != nil {
+			return nil, err
+		}
+		if mode == AutoUnify {
+			// Nothing has a register to unify to
+			return &Result{Text: doc.Render()}, nil
+		}
+	}
+
+	result := &Result{Target: mode.targetRegister()}
+	input := newRuneCounter(doc.Render())
+	in, out := 0, 0 // 変換前・変換後のテキスト中の位置（バイト）
+	var errs []error
+	for i := range doc.Paragraphs {
+		p := &doc.Paragraphs[i]
+		for j := range p.Sentences {
+			s := &p.Sentences[j]
+			in += len(s.Leading)
+			out += len(s.Leading)
+			if len(s.Spans) == 0 {
+				in += len(s.Trailing)
+				out += len(s.Trailing)
+				continue
+			}
+
+			masked, m := maskSpans(s.Spans)
+			sr, err := c.convertSentenceSafely(masked, mode)
+			if err != nil {
+				err = &SentenceError{Index: len(result.Sentences), Text: s.Text(), Err: err}
+				if c.errorPolicy != ErrorContinue {
+					return nil, err
+				}
+				errs = append(errs, err)
+				sr = SentenceResult{Original: masked, Converted: masked, SkipReason: SkipError}
+			}
+			if sr.Converted != masked {
+				s.Spans = buildSpans(sr.Converted, m, s.Offset)
+			}
+			m.restoreSentence(&sr)
+			sr.Input = input.rangeOf(in, in+len(sr.Original))
+			for k := range sr.Edits {
+				e := &sr.Edits[k]
+				e.Start += in
+				e.End += in
+				e.StartUTF16 = input.utf16Offset(e.Start)
+				e.EndUTF16 = input.utf16Offset(e.End)
+			}
+			sr.Output = Range{Start: out, End: out + len(sr.Converted)}
+			in = sr.Input.End + len(s.Trailing)
+			out = sr.Output.End + len(s.Trailing)
+			c.logger.Debug("converted sentence", "index", len(result.Sentences),
+				"original", sr.Original, "converted", sr.Converted, "skip", sr.SkipReason.String())
+
+			for k := range sr.Warnings {
+				sr.Warnings[k].SentenceIndex = len(result.Sentences)
+			}
+			result.Warnings = append(result.Warnings, sr.Warnings...)
+			result.alignment = append(result.alignment, sr.Edits...)
+			sr.Edits = mergeEdits(sr.Edits)
+			result.Edits = append(result.Edits, sr.Edits...)
+			result.Sentences = append(result.Sentences, sr)
+		}
+	}
+	result.Text = doc.Render()
+
+	// Rune offsets in the output are known only once it is rendered
+	output := newRuneCounter(result.Text)
+	for i := range result.Sentences {
+		sr := &result.Sentences[i]
+		sr.Output = output.rangeOf(sr.Output.Start, sr.Output.End)
+	}
+	return result, errors.Join(errs...)
+}
+
+// unifyingMode returns the mode that converts the document to its dominant
+// register, or AutoUnify when no sentence has a register.
+func (c *Converter) unifyingMode(doc *Document) (ConversionMode, error) {
+	report, err := c.detectDocumentStyle(doc)
+	if err != nil {
+		return AutoUnify, err
+	}
+	if report.IsMixed(c.mixedThreshold) {
+		return AutoUnify, fmt.Errorf("%w: %d polite and %d casual sentences", ErrMixedStyle,
+			report.Counts[StylePolite], report.Counts[StyleDa]+report.Counts[StyleDearu])
+	}
+
+	switch report.DominantRegister() {
+	case RegisterPolite:
+		return CasualToPolite, nil
+	case RegisterCasual:
+		return PoliteToCasual, nil
+	}
+	return AutoUnify, nil
+}
+
+// runeCounter converts byte offsets in a text to rune offsets and UTF-16
+// offsets. Offsets must be passed in increasing order, so that the text is
+// scanned only once.
+type runeCounter struct {
+	text  string
+	bytes int // 数え終えた位置（バイト）
+	runes int // 数え終えた位置（文字）
+	units int // 数え終えた位置（UTF-16 コード単位）
+}
+
+// newRuneCounter returns a runeCounter for the text.
+func newRuneCounter(text string) *runeCounter {
+	return &runeCounter{text: text}
+}
+
+// runeOffset returns the rune offset of the byte offset.
+func (rc *runeCounter) runeOffset(offset int) int {
+	rc.advance(offset)
+	return rc.runes
+}
+
+// utf16Offset returns the offset in UTF-16 code units of the byte offset.
+func (rc *runeCounter) utf16Offset(offset int) int {
+	rc.advance(offset)
+	return rc.units
+}
+
+// advance counts the text up to the byte offset.
+func (rc *runeCounter) advance(offset int) {
+	if offset < rc.bytes {
+		rc.bytes, rc.runes, rc.units = 0, 0, 0
+	}
+	for _, r := range rc.text[rc.bytes:offset] {
+		rc.runes++
+		rc.units += utf16.RuneLen(r)
+	}
+	rc.bytes = offset
+}
+
+// rangeOf returns the Range of the bytes from start to end.
+func (rc *runeCounter) rangeOf(start, end int) Range {
+	return Range{Start: start, End: end, RuneStart: rc.runeOffset(start), RuneEnd: rc.runeOffset(end)}
+}
@@ -0,0 +1,112 @@
+package kjconv
+
+import (
+	"testing"
+)
+
+func TestParseDocument_RoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"今日は晴れだ。",
+		"\n\n今日は晴れだ。\n\n\n明日は雨だ。\n",
+		"詳細は https://example.com/?q=1. を見る。「晴れだ。」と言った。\r\n\r\n次へ。",
+		"「晴れだ。\n\n雨だ。」と言った。",
+	}
+
+	for _, input := range inputs {
+		if got := ParseDocument(input).Render(); got != input {
+			t.Errorf("ParseDocument(%q).Render() = %q", input, got)
+		}
+	}
+}
+
+func TestParseDocument_Structure(t *testing.T) {
+	input := "詳細は`a.go`を見る。「行く」と言った。\n\n次へ。"
+	doc := ParseDocument(input)
+
+	if len(doc.Paragraphs) != 2 {
+		t.Fatalf("len(Paragraphs) = %d, expected 2", len(doc.Paragraphs))
+	}
+	if got := len(doc.Paragraphs[0].Sentences); got != 2 {
+		t.Fatalf("len(Paragraphs[0].Sentences) = %d, expected 2", got)
+	}
+	if p := doc.Paragraphs[1]; p.Offset != len("詳細は`a.go`を見る。「行く」と言った。\n\n") {
+		t.Errorf("Paragraphs[1].Offset = %d", p.Offset)
+	}
+
+	type span struct {
+		kind SpanKind
+		text string
+	}
+	tests := []struct {
+		sentence Sentence
+		expected []span
+	}{
+		{
+			sentence: doc.Paragraphs[0].Sentences[0],
+			expected: []span{{SpanText, "詳細は"}, {SpanProtected, "`a.go`"}, {SpanText, "を見る。"}},
+		},
+		{
+			sentence: doc.Paragraphs[0].Sentences[1],
+			expected: []span{{SpanQuote, "「行く」"}, {SpanText, "と言った。"}},
+		},
+	}
+
+	for _, tt := range tests {
+		if len(tt.sentence.Spans) != len(tt.expected) {
+			t.Errorf("Spans of %q = %+v, expected %+v", tt.sentence.Text(), tt.sentence.Spans, tt.expected)
+			continue
+		}
+		for i, s := range tt.sentence.Spans {
+			if s.Kind != tt.expected[i].kind || s.Text != tt.expected[i].text {
+				t.Errorf("Spans[%d] = %v %q, expected %v %q", i, s.Kind, s.Text, tt.expected[i].kind, tt.expected[i].text)
+			}
+			if input[s.Offset:s.end()] != s.Text {
+				t.Errorf("Spans[%d].Offset = %d does not point at %q", i, s.Offset, s.Text)
+			}
+		}
+	}
+
+	quote := doc.Paragraphs[0].Sentences[1].Spans[0]
+	if len(quote.Spans) != 1 || quote.Spans[0].Text != "行く" || input[quote.Spans[0].Offset:quote.Spans[0].end()] != "行く" {
+		t.Errorf("quote.Spans = %+v, expected the content 行く", quote.Spans)
+	}
+}
+
+func TestConvertDocument(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	// A document built by a format adapter, e.g. from "これは**重要**だ。"
+	doc := &Document{Paragraphs: []Paragraph{{Sentences: []Sentence{{
+		Spans: []Span{
+			{Kind: SpanText, Text: "これは", Offset: 0},
+			{Kind: SpanMarkup, Text: "**", Offset: 9},
+			{Kind: SpanText, Text: "重要", Offset: 11},
+			{Kind: SpanMarkup, Text: "**", Offset: 17},
+			{Kind: SpanText, Text: "だ。", Offset: 19},
+		},
+	}}}}}
+
+	result, err := converter.ConvertDocument(doc, CasualToPolite)
+	if err != nil {
+		t.Fatalf("ConvertDocument() failed: %v", err)
+	}
+	if expected := "これは**重要**です。"; result.Text != expected {
+		t.Errorf("ConvertDocument().Text = %q, expected %q", result.Text, expected)
+	}
+	if got := doc.Render(); got != result.Text {
+		t.Errorf("Render() = %q, expected %q", got, result.Text)
+	}
+
+	spans := doc.Paragraphs[0].Sentences[0].Spans
+	last := spans[len(spans)-1]
+	if last.Kind != SpanText || last.Text != "です。" || last.Offset != 19 {
+		t.Errorf("last span = %+v, expected the converted text at offset 19", last)
+	}
+	if spans[3].Kind != SpanMarkup || spans[3].Offset != 17 {
+		t.Errorf("Spans[3] = %+v, expected the markup at offset 17", spans[3])
+	}
+}
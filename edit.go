@@ -0,0 +1,289 @@
+package kjconv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TextEdit replaces a part of the input text. Edits are computed from the
+// morphemes that a conversion changed, inserted or removed, so that an editor
+// can apply them without replacing whole sentences.
+type TextEdit struct {
+	Start, End           int    // 置き換える範囲（バイト位置）
+	StartUTF16, EndUTF16 int    // 置き換える範囲（UTF-16 コード単位の位置）
+	NewText              string // 置き換え後の文字列
+}
+
+// ConvertEdits converts the input text like Convert and returns the changes as
+// edits against the input, in increasing order of position. Applying them to
+// the input with ApplyEdits reproduces the output of Convert exactly. Under
+// ErrorContinue the edits are returned together with the errors of the
+// sentences that could not be converted.
+func (c *Converter) ConvertEdits(text string, mode ConversionMode) ([]TextEdit, error) {
+	result, err := c.ConvertDetailed(text, mode)
+	if result == nil {
+		return nil, err
+	}
+	return result.Edits, err
+}
+
+// MapToSource returns the byte offset in the input text that corresponds to a
+// byte offset in the converted text (Text). Text that was not changed maps
+// one to one. Offsets in a changed morpheme map to the start of the morpheme
+// it replaces, and offsets in an inserted morpheme, such as the ます of
+// 読みます, map to the position where it was inserted.
+func (r *Result) MapToSource(outputOffset int) int {
+	return mapToSource(r.alignment, min(max(outputOffset, 0), len(r.Text)))
+}
+
+// MapToOutput returns the byte offset in the converted text (Text) that
+// corresponds to a byte offset in the input text. Text that was not changed
+// maps one to one. Offsets in a changed or removed morpheme map to the start
+// of its replacement.
+func (r *Result) MapToOutput(sourceOffset int) int {
+	return min(mapToOutput(r.alignment, max(sourceOffset, 0)), len(r.Text))
+}
+
+// ApplyEdits applies edits given in increasing order of position to text,
+// using their byte offsets.
+func ApplyEdits(text string, edits []TextEdit) (string, error) {
+	var b strings.Builder
+	start := 0 // まだ書き出していないテキストの開始位置
+	for _, e := range edits {
+		if e.Start < start || e.End < e.Start || e.End > len(text) {
+			return "", fmt.Errorf("invalid edit: [%d, %d) after %d in text of %d bytes", e.Start, e.End, start, len(text))
+		}
+		b.WriteString(text[start:e.Start])
+		b.WriteString(e.NewText)
+		start = e.End
+	}
+	b.WriteString(text[start:])
+	return b.String(), nil
+}
+
+// mergeEdits joins edits that touch each other, such as a changed morpheme
+// and a morpheme inserted after it, into single edits.
+func mergeEdits(edits []TextEdit) []TextEdit {
+	var merged []TextEdit
+	for _, e := range edits {
+		if n := len(merged); n > 0 && merged[n-1].End == e.Start {
+			merged[n-1].End = e.End
+			merged[n-1].EndUTF16 = e.EndUTF16
+			merged[n-1].NewText += e.NewText
+			continue
+		}
+		merged = append(merged, e)
+	}
+	return merged
+}
+
+// mapToSource returns the offset in the input of the offset in the output of
+// the edits. Offsets in replaced or inserted text are mapped to the start of
+// the text they replace.
+func mapToSource(edits []TextEdit, offset int) int {
+	shift := 0 // 出力での位置のずれ
+	for _, e := range edits {
+		start := e.Start + shift
+		if offset < start {
+			break
+		}
+		if offset < start+len(e.NewText) {
+			return e.Start
+		}
+		shift += len(e.NewText) - (e.End - e.Start)
+	}
+	return offset - shift
+}
+
+// mapToOutput returns the offset in the output of the edits of the offset in
+// the input. Offsets in replaced or removed text are mapped to the start of
+// their replacement.
+func mapToOutput(edits []TextEdit, offset int) int {
+	shift := 0 // 出力での位置のずれ
+	for _, e := range edits {
+		if offset < e.Start {
+			break
+		}
+		if offset < e.End {
+			return e.Start + shift
+		}
+		shift += len(e.NewText) - (e.End - e.Start)
+	}
+	return offset + shift
+}
+
+// alignMorphemes aligns text, from which the morphemes of a conversion were
+// analysed, with the concatenation of the morphemes. The alignment is a list
+// of edits with one edit for each morpheme that was changed, inserted or
+// removed; morphemes that keep their span and surface are left in place.
+func alignMorphemes(text string, morphemes []MorphemeInfo) []TextEdit {
+	var edits []TextEdit
+	pos := 0 // 位置が対応づいた入力の終了位置
+
+	// remove removes the text from pos to end. Morphemes inserted at pos
+	// replace it, so that the removed text maps to them.
+	remove := func(end int) {
+		k := len(edits)
+		for k > 0 && edits[k-1].Start == pos && edits[k-1].End == pos {
+			k--
+		}
+		if k == len(edits) {
+			edits = append(edits, TextEdit{Start: pos, End: end})
+			return
+		}
+		edits[k].End = end
+		for i := k + 1; i < len(edits); i++ {
+			edits[i].Start, edits[i].End = end, end
+		}
+	}
+
+	for _, m := range morphemes {
+		if m.end == 0 || m.start < pos || m.end > len(text) {
+			// Inserted, or moved from its place
+			edits = append(edits, TextEdit{Start: pos, End: pos, NewText: m.Surface})
+			continue
+		}
+		if text[m.start:m.end] == m.Surface {
+			if m.start > pos {
+				remove(m.start)
+			}
+		} else {
+			// Removed morphemes before a changed one are replaced with it
+			edits = append(edits, TextEdit{Start: pos, End: m.end, NewText: m.Surface})
+		}
+		pos = m.end
+	}
+	if pos < len(text) {
+		remove(len(text))
+	}
+	return edits
+}
+
+// inheritSpans gives the morphemes generated in place of original the spans of
+// the original morphemes they correspond to: those they reproduce at the start
+// and at the end, and in between those of the same part of speech with the
+// same base form or inflection type in the same order, such as 読み for 読む in
+// 読む → 読みます. The generated morphemes without a span are reported as
+// inserted.
+func inheritSpans(generated, original []MorphemeInfo) {
+	n := 0
+	for n < len(generated) && n < len(original) && generated[n].Surface == original[n].Surface {
+		generated[n].start, generated[n].end = original[n].start, original[n].end
+		n++
+	}
+	i, j := len(generated), len(original)
+	for i > n && j > n && generated[i-1].Surface == original[j-1].Surface {
+		i, j = i-1, j-1
+		generated[i].start, generated[i].end = original[j].start, original[j].end
+	}
+
+	next := n // 対応を探す元の形態素の開始位置
+	for g := n; g < i; g++ {
+		for k := next; k < j; k++ {
+			if correspond(original[k], generated[g]) {
+				generated[g].start, generated[g].end = original[k].start, original[k].end
+				next = k + 1
+				break
+			}
+		}
+	}
+}
+
+// correspond reports whether a generated morpheme takes the place of an
+// original one, as 読ん takes the place of 読み and だ of た in 読みました → 読んだ.
+func correspond(original, generated MorphemeInfo) bool {
+	if original.PartOfSpeech != generated.PartOfSpeech {
+		return false
+	}
+	return original.BaseForm == generated.BaseForm ||
+		(original.InflectionType != "" && original.InflectionType == generated.InflectionType)
+}
+
+// shiftEdits moves the edits by offset bytes, for edits of a part of a text
+// that starts at offset.
+func shiftEdits(edits []TextEdit, offset int) []TextEdit {
+	for i := range edits {
+		edits[i].Start += offset
+		edits[i].End += offset
+	}
+	return edits
+}
+
+// composeEdits returns the edits that have the effect of applying first and
+// then second, where second is given against the result of first and text is
+// the result of both. Edits of the two that overlap in the intermediate text
+// are merged; the others are kept as they are.
+func composeEdits(first, second []TextEdit, text string) []TextEdit {
+	if len(first) == 0 {
+		return second
+	}
+	if len(second) == 0 {
+		return first
+	}
+
+	var edits []TextEdit
+	i, j := 0, 0
+	firstShift, secondShift := 0, 0 // 中間のテキストでの位置から見た、入力・出力でのずれ
+
+	// The ranges of the next edits of first and second in the intermediate text
+	nextFirst := func() (int, int) {
+		e := first[i]
+		return e.Start + firstShift, e.Start + firstShift + len(e.NewText)
+	}
+	nextSecond := func() (int, int) {
+		return second[j].Start, second[j].End
+	}
+
+	for i < len(first) || j < len(second) {
+		// Start with the edit that comes first, an empty range before others
+		takeFirst := j == len(second)
+		if i < len(first) && j < len(second) {
+			s1, e1 := nextFirst()
+			s2, _ := nextSecond()
+			takeFirst = s1 < s2 || (s1 == s2 && e1 == s1)
+		}
+		inputShift, outputShift := firstShift, secondShift
+		var start, end int
+		if takeFirst {
+			start, end = nextFirst()
+			firstShift += len(first[i].NewText) - (first[i].End - first[i].Start)
+			i++
+		} else {
+			start, end = nextSecond()
+			secondShift += len(second[j].NewText) - (second[j].End - second[j].Start)
+			j++
+		}
+
+		// Absorb the edits that overlap the range, including empty ranges
+		// strictly inside it
+		overlaps := func(s, e int) bool {
+			return s < end && (s < e || s > start)
+		}
+		for absorbed := true; absorbed; {
+			absorbed = false
+			if i < len(first) {
+				if s, e := nextFirst(); overlaps(s, e) {
+					end = max(end, e)
+					firstShift += len(first[i].NewText) - (first[i].End - first[i].Start)
+					i++
+					absorbed = true
+				}
+			}
+			if j < len(second) {
+				if s, e := nextSecond(); overlaps(s, e) {
+					end = max(end, e)
+					secondShift += len(second[j].NewText) - (second[j].End - second[j].Start)
+					j++
+					absorbed = true
+				}
+			}
+		}
+
+		edits = append(edits, TextEdit{
+			Start:   start - inputShift,
+			End:     end - firstShift,
+			NewText: text[start+outputShift : end+secondShift],
+		})
+	}
+	return edits
+}
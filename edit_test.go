@@ -0,0 +1,208 @@
+package kjconv
+
+import (
+	"fmt"
+	"testing"
+	"unicode/utf16"
+)
+
+func TestConvertEdits(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		mode     ConversionMode
+		expected []string // "開始-終了 置き換え後" (UTF-16)
+	}{
+		{"本を読む。", CasualToPolite, []string{"2-4 読みます"}},
+		{"この花は美しい。", CasualToPolite, []string{"7-7 です"}},
+		{"今日は晴れだ。", CasualToPolite, []string{"5-6 です"}},
+		{"本を読みました。", PoliteToCasual, []string{"2-7 読んだ"}},
+		{"雨が降ったので、家にいた。", CasualToPolite, []string{"11-11 まし"}},
+		{"だから、本を読む。", CasualToPolite, []string{"0-3 ですから", "6-8 読みます"}},
+		{"🍎を食べる。", CasualToPolite, []string{"3-6 食べます"}},
+		{"本を読みます。", CasualToPolite, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			edits, err := converter.ConvertEdits(tt.input, tt.mode)
+			if err != nil {
+				t.Fatalf("ConvertEdits() failed: %v", err)
+			}
+			var got []string
+			for _, e := range edits {
+				got = append(got, fmt.Sprintf("%d-%d %s", e.StartUTF16, e.EndUTF16, e.NewText))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Errorf("ConvertEdits(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertEdits_Apply(t *testing.T) {
+	converter, err := NewConverter(
+		WithQuotePolicy(QuoteConvert, '『'),
+		WithNounEndingPolicy(NounEndingAppend, NounEndingLeave),
+	)
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	inputs := []string{
+		"本を読む。字を書く。\n\n空が青い。",
+		"  雨だ。\r\n晴れだ！",
+		"彼は「行くよ」と言った。",
+		"答えは『明日は晴れだ。雨は降らない。』と書いた。",
+		"詳細は https://example.com/a を見る。",
+		"**本を読む**。`go test` を実行する。",
+		"今日は楽しかった😊",
+		"明日は雨だ（笑）",
+		"だから、本を読む。",
+		"美しい花。",
+		"本を読みます。字を書きます。",
+	}
+
+	for _, input := range inputs {
+		for _, mode := range []ConversionMode{CasualToPolite, PoliteToCasual} {
+			result, err := converter.ConvertDetailed(input, mode)
+			if err != nil {
+				t.Fatalf("ConvertDetailed(%q) failed: %v", input, err)
+			}
+			applied, err := ApplyEdits(input, result.Edits)
+			if err != nil {
+				t.Fatalf("ApplyEdits(%q) failed: %v", input, err)
+			}
+			if applied != result.Text {
+				t.Errorf("ApplyEdits(%q, %v) = %q, expected %q", input, mode, applied, result.Text)
+			}
+
+			for _, e := range result.Edits {
+				if start := len(utf16.Encode([]rune(input[:e.Start]))); e.StartUTF16 != start {
+					t.Errorf("%q: StartUTF16 = %d, expected %d", input, e.StartUTF16, start)
+				}
+				if end := len(utf16.Encode([]rune(input[:e.End]))); e.EndUTF16 != end {
+					t.Errorf("%q: EndUTF16 = %d, expected %d", input, e.EndUTF16, end)
+				}
+			}
+		}
+	}
+}
+
+func TestApplyEdits_Invalid(t *testing.T) {
+	edits := []TextEdit{{Start: 3, End: 6, NewText: "x"}, {Start: 0, End: 3, NewText: "y"}}
+	if _, err := ApplyEdits("あいう", edits); err == nil {
+		t.Error("ApplyEdits() with unordered edits succeeded, expected an error")
+	}
+	if _, err := ApplyEdits("あ", []TextEdit{{Start: 0, End: 6}}); err == nil {
+		t.Error("ApplyEdits() with an edit out of range succeeded, expected an error")
+	}
+}
+
+func TestResult_MapOffsets(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		mode     ConversionMode
+		options  []Option
+		toSource map[int]int // 出力中の位置 → 入力中の位置
+		toOutput map[int]int // 入力中の位置 → 出力中の位置
+	}{
+		{
+			// 本を読む。 → 本を読みます。
+			name:     "inserted ます",
+			input:    "本を読む。",
+			mode:     CasualToPolite,
+			toSource: map[int]int{0: 0, 6: 6, 9: 6, 12: 12, 15: 12, 18: 12, 21: 15},
+			toOutput: map[int]int{3: 3, 6: 6, 9: 6, 12: 18, 15: 21},
+		},
+		{
+			name:     "inserted ます without the predicate rule",
+			input:    "本を読む。",
+			mode:     CasualToPolite,
+			options:  []Option{WithDisabledRules(RulePredicate)},
+			toSource: map[int]int{6: 6, 9: 6, 12: 12, 15: 12, 18: 12, 21: 15},
+			toOutput: map[int]int{6: 6, 12: 18, 15: 21},
+		},
+		{
+			// 本を読みました。 → 本を読んだ。
+			name:     "removed まし",
+			input:    "本を読みました。",
+			mode:     PoliteToCasual,
+			toSource: map[int]int{6: 6, 9: 6, 12: 12, 15: 21, 18: 24},
+			toOutput: map[int]int{6: 6, 12: 12, 15: 12, 18: 12, 21: 15, 24: 18},
+		},
+		{
+			// 詳細は https://example.com を見る。雨だ。 → 詳細は https://example.com を見ます。雨です。
+			name:     "across sentences and protected spans",
+			input:    "詳細は https://example.com を見る。雨だ。",
+			mode:     CasualToPolite,
+			toSource: map[int]int{10: 10, 30: 30, 33: 33, 36: 39, 42: 39, 45: 42, 48: 45, 51: 45, 54: 48, 57: 51},
+			toOutput: map[int]int{30: 30, 33: 33, 36: 33, 39: 42, 42: 45, 45: 48, 48: 54, 51: 57},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := NewConverter(tt.options...)
+			if err != nil {
+				t.Fatalf("NewConverter() failed: %v", err)
+			}
+			result, err := converter.ConvertDetailed(tt.input, tt.mode)
+			if err != nil {
+				t.Fatalf("ConvertDetailed() failed: %v", err)
+			}
+			for output, source := range tt.toSource {
+				if got := result.MapToSource(output); got != source {
+					t.Errorf("MapToSource(%d) in %q = %d, expected %d", output, result.Text, got, source)
+				}
+			}
+			for source, output := range tt.toOutput {
+				if got := result.MapToOutput(source); got != output {
+					t.Errorf("MapToOutput(%d) in %q = %d, expected %d", source, tt.input, got, output)
+				}
+			}
+		})
+	}
+}
+
+func TestResult_MapOffsets_Monotonic(t *testing.T) {
+	converter, err := NewConverter(WithQuotePolicy(QuoteConvert))
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	input := "彼は「雨だ。」と言った😊\n\n美しい花だ。**本を読む**（笑）"
+	result, err := converter.ConvertDetailed(input, CasualToPolite)
+	if err != nil {
+		t.Fatalf("ConvertDetailed() failed: %v", err)
+	}
+
+	prev := 0
+	for i := 0; i <= len(result.Text); i++ {
+		source := result.MapToSource(i)
+		if source < prev || source > len(input) {
+			t.Fatalf("MapToSource(%d) = %d after %d", i, source, prev)
+		}
+		prev = source
+	}
+	if prev != len(input) {
+		t.Errorf("MapToSource(%d) = %d, expected %d", len(result.Text), prev, len(input))
+	}
+
+	prev = 0
+	for i := 0; i <= len(input); i++ {
+		output := result.MapToOutput(i)
+		if output < prev || output > len(result.Text) {
+			t.Fatalf("MapToOutput(%d) = %d after %d", i, output, prev)
+		}
+		prev = output
+	}
+	if prev != len(result.Text) {
+		t.Errorf("MapToOutput(%d) = %d, expected %d", len(input), prev, len(result.Text))
+	}
+}
@@ -0,0 +1,89 @@
+package kjconv
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedMode is returned for a ConversionMode that is not one of the
+// defined modes.
+var ErrUnsupportedMode = errors.New("unsupported conversion mode")
+
+// SentenceError is returned when a sentence cannot be converted, including
+// when converting it panics. Index is the index of the sentence in
+// Result.Sentences and Text is the sentence as it appears in the input.
+type SentenceError struct {
+	Index int    // 文の番号（Result.Sentences での位置）
+	Text  string // 変換できなかった文
+	Err   error  // 変換できなかった理由
+}
+
+// Error returns the index and the text of the sentence with the reason.
+func (e *SentenceError) Error() string {
+	return fmt.Sprintf("sentence %d %q: %v", e.Index, e.Text, e.Err)
+}
+
+// Unwrap returns the reason, so that errors.Is and errors.As see through it.
+func (e *SentenceError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorPolicy controls what a conversion does when a sentence cannot be converted.
+type ErrorPolicy int
+
+const (
+	// ErrorAbort stops at the first sentence that cannot be converted and
+	// returns its *SentenceError without a result. This is the default.
+	ErrorAbort ErrorPolicy = iota
+	// ErrorContinue leaves the sentences that cannot be converted unchanged,
+	// with SkipError as their skip reason, and converts the others. The
+	// result is returned together with the *SentenceError of each failed
+	// sentence joined with errors.Join.
+	ErrorContinue
+)
+
+// String returns a human readable name of the policy.
+func (p ErrorPolicy) String() string {
+	switch p {
+	case ErrorAbort:
+		return "abort"
+	case ErrorContinue:
+		return "continue"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseErrorPolicy parses the name of an error policy as returned by String.
+func ParseErrorPolicy(s string) (ErrorPolicy, error) {
+	switch s {
+	case "abort":
+		return ErrorAbort, nil
+	case "continue":
+		return ErrorContinue, nil
+	}
+	return ErrorAbort, fmt.Errorf("unknown error policy: %q", s)
+}
+
+// SetErrorPolicy sets what a conversion does when a sentence cannot be
+// converted. The default is ErrorAbort.
+func (c *Converter) SetErrorPolicy(p ErrorPolicy) {
+	c.errorPolicy = p
+}
+
+// validMode reports whether mode is one of the defined conversion modes.
+func validMode(mode ConversionMode) bool {
+	return mode == CasualToPolite || mode == PoliteToCasual || mode == AutoUnify
+}
+
+// convertSentenceSafely converts a sentence like convertSentence and returns a
+// panic during the conversion as an error, so that one unusual sentence does
+// not bring down the conversion of the whole document.
+func (c *Converter) convertSentenceSafely(sentence string, mode ConversionMode) (sr SentenceResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			sr, err = SentenceResult{Original: sentence}, fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return c.convertSentence(sentence, mode)
+}
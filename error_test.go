@@ -0,0 +1,88 @@
+package kjconv
+
+import (
+	"errors"
+	"testing"
+)
+
+// brokenRule indexes past its window, as a rule written for other morpheme
+// sequences might.
+type brokenRule struct {
+	endingRule
+}
+
+func (r brokenRule) Rewrite(window []MorphemeInfo) []MorphemeInfo {
+	return []MorphemeInfo{window[len(window)]}
+}
+
+func TestConvert_UnsupportedMode(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+	if _, err := converter.Convert("本を読む。", ConversionMode(9)); !errors.Is(err, ErrUnsupportedMode) {
+		t.Errorf("Convert() error = %v, expected ErrUnsupportedMode", err)
+	}
+}
+
+func TestConvert_SentencePanic(t *testing.T) {
+	input := "本を読む。雨が降る。字を書く。"
+	rule := brokenRule{endingRule{id: "broken", direction: CasualToPolite, priority: 1000, from: "降る"}}
+
+	t.Run("abort", func(t *testing.T) {
+		converter, err := NewConverter(WithRule(rule))
+		if err != nil {
+			t.Fatalf("NewConverter() failed: %v", err)
+		}
+		result, err := converter.ConvertDetailed(input, CasualToPolite)
+		var sentenceErr *SentenceError
+		if !errors.As(err, &sentenceErr) {
+			t.Fatalf("ConvertDetailed() error = %v, expected a *SentenceError", err)
+		}
+		if sentenceErr.Index != 1 || sentenceErr.Text != "雨が降る。" {
+			t.Errorf("SentenceError = %d %q, expected 1 %q", sentenceErr.Index, sentenceErr.Text, "雨が降る。")
+		}
+		if result != nil {
+			t.Errorf("ConvertDetailed() = %q, expected no result", result.Text)
+		}
+	})
+
+	t.Run("continue", func(t *testing.T) {
+		converter, err := NewConverter(WithRule(rule), WithErrorPolicy(ErrorContinue))
+		if err != nil {
+			t.Fatalf("NewConverter() failed: %v", err)
+		}
+		result, err := converter.ConvertDetailed(input, CasualToPolite)
+		var sentenceErr *SentenceError
+		if !errors.As(err, &sentenceErr) || sentenceErr.Index != 1 {
+			t.Fatalf("ConvertDetailed() error = %v, expected a *SentenceError of sentence 1", err)
+		}
+
+		expected := "本を読みます。雨が降る。字を書きます。"
+		if result.Text != expected {
+			t.Errorf("ConvertDetailed().Text = %q, expected %q", result.Text, expected)
+		}
+		if sr := result.Sentences[1]; sr.SkipReason != SkipError || sr.Converted != sr.Original {
+			t.Errorf("Sentences[1] = %q (%v), expected it unchanged with %v", sr.Converted, sr.SkipReason, SkipError)
+		}
+		if applied, err := ApplyEdits(input, result.Edits); err != nil || applied != expected {
+			t.Errorf("ApplyEdits() = %q, %v, expected %q", applied, err, expected)
+		}
+
+		text, err := converter.Convert(input, CasualToPolite)
+		if text != expected || err == nil {
+			t.Errorf("Convert() = %q, %v, expected %q with an error", text, err, expected)
+		}
+	})
+}
+
+func TestParseErrorPolicy(t *testing.T) {
+	for _, p := range []ErrorPolicy{ErrorAbort, ErrorContinue} {
+		if got, err := ParseErrorPolicy(p.String()); err != nil || got != p {
+			t.Errorf("ParseErrorPolicy(%q) = %v, %v, expected %v", p.String(), got, err, p)
+		}
+	}
+	if _, err := ParseErrorPolicy("ignore"); err == nil {
+		t.Error("ParseErrorPolicy(\"ignore\") succeeded, expected an error")
+	}
+}
@@ -0,0 +1,523 @@
+package kjconv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GeneratePredicate produces the morphemes of the predicate described by p,
+// from its head to its end, e.g. 読み|ませ|ん|でし|た for the verb 読む in the
+// polite negative past. The head may be in any inflected form; only its base
+// form and inflection type are used. Final particles are not generated, so
+// ModalityQuestion is generated like ModalityNone, except that a noun or
+// na-adjective drops the casual copula だ as it does before か (学生か).
+//
+// The copula is generated in the form of p.Copula (学生だ, 学生である,
+// 学生じゃない), a Desiderative verb with たい (読みたいです), an Explanatory
+// predicate with の (読むのです), and ConjectureKamoshirenai with かもしれない.
+//
+// The morphemes carry the part of speech, inflection type, inflection form and
+// base form that IPADIC gives to the same text. Expressions with more than one
+// polite form are generated in the default Style.
+func GeneratePredicate(p Predicate) ([]MorphemeInfo, error) {
+	return generatePredicate(p, Style{})
+}
+
+// generatePredicate generates the predicate like GeneratePredicate in the style.
+func generatePredicate(p Predicate, style Style) ([]MorphemeInfo, error) {
+	switch p.Modality {
+	case ModalityNone, ModalityQuestion, ModalityVolitional, ModalityConjecture:
+	default:
+		return nil, fmt.Errorf("cannot generate a predicate with %s modality", p.Modality)
+	}
+	if p.Modality == ModalityVolitional && (p.Class != PredicateVerb || p.Polarity == PolarityNegative || p.Tense == TensePast ||
+		p.Desiderative || p.Explanatory) {
+		return nil, fmt.Errorf("cannot generate a volitional %s predicate in %s %s", p.Class, p.Polarity, p.Tense)
+	}
+	if p.Modality == ModalityConjecture && (p.Conjecture == ConjectureOther || p.Copula == CopulaWritten) {
+		return nil, fmt.Errorf("cannot generate a %s conjecture with the %s copula", p.Conjecture, p.Copula)
+	}
+
+	if p.Modality == ModalityConjecture && p.Conjecture == ConjectureKamoshirenai {
+		// 降るかもしれません: the predicate before かも is casual, and a
+		// noun takes no copula (雨かもしれない)
+		inner := p
+		inner.Modality, inner.Conjecture, inner.Politeness = ModalityNone, ConjectureDarou, RegisterCasual
+		result, err := generateBody(inner, style, copulaBare)
+		if err != nil {
+			return nil, err
+		}
+		return append(result, kamoshirenai(p.Politeness)...), nil
+	}
+	return generateBody(p, style, copulaFinal)
+}
+
+// copulaUse is how the casual non-past affirmative copula after a noun is
+// generated.
+type copulaUse int
+
+const (
+	copulaFinal       copulaUse = iota // 学生だ
+	copulaBare                         // 学生（かもしれない）
+	copulaAttributive                  // 学生な（のだ）
+)
+
+// generateBody generates the predicate without かもしれない, using the copula
+// after a noun as given.
+func generateBody(p Predicate, style Style, use copulaUse) ([]MorphemeInfo, error) {
+	if p.Explanatory {
+		// 読むのです: the predicate before の is casual, and の takes the copula
+		inner := p
+		inner.Explanatory, inner.Modality, inner.Politeness, inner.Copula = false, ModalityNone, RegisterCasual, CopulaPlain
+		result, err := generateBody(inner, style, copulaAttributive)
+		if err != nil {
+			return nil, err
+		}
+		copula := Predicate{
+			Head: nominalizer(), Class: PredicateNounCopula,
+			Politeness: p.Politeness, Modality: p.Modality, Conjecture: p.Conjecture, Copula: p.Copula,
+		}
+		return append(result, generateNominal(copula, use)...), nil
+	}
+
+	var result []MorphemeInfo
+	var ok bool
+	switch p.Class {
+	case PredicateVerb:
+		result, ok = generateVerb(p, style)
+	case PredicateAdjective:
+		result, ok = generateAdjective(p.Head, p, style)
+	case PredicateNaAdjective, PredicateNounCopula:
+		result = generateNominal(p, use)
+		ok = true
+	}
+	if !ok {
+		return nil, fmt.Errorf("cannot conjugate %q (%s)", p.Head.Surface, p.Head.InflectionType)
+	}
+	return result, nil
+}
+
+// generateVerb generates a verb followed by its aspect chain, e.g. 読ん|で|い|ます.
+func generateVerb(p Predicate, style Style) ([]MorphemeInfo, bool) {
+	chain := []MorphemeInfo{p.Head}
+	for _, a := range p.Aspect {
+		chain = append(chain, aspectVerb(a))
+	}
+
+	var result []MorphemeInfo
+	for _, v := range chain[:len(chain)-1] {
+		v, ok := conjugate(v, pastForm(v))
+		if !ok {
+			return nil, false
+		}
+		result = append(result, v, teParticle(isVoicedTa(v)))
+	}
+
+	v := chain[len(chain)-1]
+	if p.Desiderative {
+		// 読みたいです: たい inflects like an adjective
+		tai, ok := generateAdjective(auxTai(), p, style)
+		if !ok {
+			return nil, false
+		}
+		predicate, ok := inflect(v, formContinuative, tai...)
+		return append(result, predicate...), ok
+	}
+
+	var predicate []MorphemeInfo
+	var ok bool
+	switch {
+	case p.Modality == ModalityVolitional && p.Politeness == RegisterPolite:
+		// 読みましょう
+		predicate, ok = inflect(v, formContinuative, auxMasu("ましょ", formVolitional), auxU())
+	case p.Modality == ModalityVolitional:
+		// 読もう
+		predicate, ok = inflect(v, formVolitional, auxU())
+	case p.Politeness == RegisterPolite && p.Modality != ModalityConjecture:
+		predicate, ok = politeVerb(v, p.Tense, p.Polarity)
+	default:
+		predicate, ok = casualVerb(v, p.Tense, p.Polarity)
+	}
+	if !ok {
+		return nil, false
+	}
+	result = append(result, predicate...)
+	if p.Modality == ModalityConjecture {
+		result = append(result, conjecture(p.Politeness)...)
+	}
+	return result, true
+}
+
+// casualVerb generates 読む, 読んだ, 読まない or 読まなかった.
+func casualVerb(v MorphemeInfo, tense Tense, polarity Polarity) ([]MorphemeInfo, bool) {
+	switch {
+	case polarity == PolarityNegative && v.BaseForm == "ある":
+		// The negative of ある is the adjective ない
+		if tense == TensePast {
+			return []MorphemeInfo{adjectiveNai("なかっ", formTa), auxTa(false)}, true
+		}
+		return []MorphemeInfo{adjectiveNai("ない", formBase)}, true
+	case polarity == PolarityNegative && tense == TensePast:
+		return inflect(v, formIrrealis, auxNai("なかっ", formTa), auxTa(false))
+	case polarity == PolarityNegative:
+		return inflect(v, formIrrealis, auxNai("ない", formBase))
+	case tense == TensePast:
+		conjugated, ok := conjugate(v, pastForm(v))
+		return []MorphemeInfo{conjugated, auxTa(isVoicedTa(conjugated))}, ok
+	default:
+		return inflect(v, formBase)
+	}
+}
+
+// politeVerb generates 読みます, 読みました, 読みません or 読みませんでした.
+func politeVerb(v MorphemeInfo, tense Tense, polarity Polarity) ([]MorphemeInfo, bool) {
+	switch {
+	case polarity == PolarityNegative && tense == TensePast:
+		return inflect(v, formContinuative, auxMasu("ませ", formIrrealis), auxN(), auxDesu("でし", formContinuative), auxTa(false))
+	case polarity == PolarityNegative:
+		return inflect(v, formContinuative, auxMasu("ませ", formIrrealis), auxN())
+	case tense == TensePast:
+		return inflect(v, formContinuative, auxMasu("まし", formContinuative), auxTa(false))
+	default:
+		return inflect(v, formContinuative, auxMasu("ます", formBase))
+	}
+}
+
+// generateAdjective generates the i-adjective a, which is the head of p or
+// the たい after it, e.g. 美しく|あり|ませ|ん.
+func generateAdjective(a MorphemeInfo, p Predicate, style Style) ([]MorphemeInfo, bool) {
+	arimasen := p.Politeness == RegisterPolite && p.Modality != ModalityConjecture &&
+		style.AdjectiveNegative == AdjectiveNegativeArimasen
+	var result []MorphemeInfo
+	var ok bool
+	switch {
+	case p.Polarity == PolarityNegative && arimasen:
+		// 美しくありません, 美しくありませんでした
+		aru := MorphemeInfo{
+			Surface: "ある", PartOfSpeech: "動詞", PartOfSpeechDetail1: "自立",
+			PartOfSpeechDetail2: "*", PartOfSpeechDetail3: "*",
+			InflectionType: "五段・ラ行", InflectionForm: formBase, BaseForm: "ある",
+		}
+		var polite []MorphemeInfo
+		if polite, ok = politeVerb(aru, p.Tense, p.Polarity); ok {
+			result, ok = inflect(a, formTe, polite...)
+		}
+	case p.Polarity == PolarityNegative && p.Tense == TensePast:
+		result, ok = inflect(a, formTe, auxNai("なかっ", formTa), auxTa(false))
+	case p.Polarity == PolarityNegative:
+		result, ok = inflect(a, formTe, auxNai("ない", formBase))
+	case p.Tense == TensePast:
+		result, ok = inflect(a, formTa, auxTa(false))
+	default:
+		result, ok = inflect(a, formBase)
+	}
+	if !ok {
+		return nil, false
+	}
+
+	switch {
+	case p.Modality == ModalityConjecture:
+		result = append(result, conjecture(p.Politeness)...)
+	case p.Politeness == RegisterPolite && (p.Polarity == PolarityAffirmative || !arimasen):
+		// 美しいです, 美しかったです, 美しくないです
+		result = append(result, auxDesu("です", formBase))
+	}
+	return result, true
+}
+
+// generateNominal generates a noun or na-adjective with a copula, e.g. 学生|でし|た.
+func generateNominal(p Predicate, use copulaUse) []MorphemeInfo {
+	result := []MorphemeInfo{p.Head}
+	polite := p.Politeness == RegisterPolite && p.Modality != ModalityConjecture
+	if p.Modality == ModalityQuestion && !polite && p.Copula == CopulaPlain {
+		// 学生か, 元気？: a casual question has no copula
+		use = copulaBare
+	}
+
+	switch {
+	case p.Polarity == PolarityNegative && p.Copula == CopulaContracted:
+		// 学生じゃない, 学生じゃありません
+		result = append(result, particleJa())
+		if polite {
+			negative, _ := politeVerb(auxAru("ある", formBase), p.Tense, p.Polarity)
+			result = append(result, negative...)
+		} else if p.Tense == TensePast {
+			result = append(result, auxNai("なかっ", formTa), auxTa(false))
+		} else {
+			result = append(result, auxNai("ない", formBase))
+		}
+	case p.Polarity == PolarityNegative:
+		// 学生ではない, 学生ではありません
+		aru := MorphemeInfo{
+			Surface: "ある", PartOfSpeech: "動詞", PartOfSpeechDetail1: "自立",
+			PartOfSpeechDetail2: "*", PartOfSpeechDetail3: "*",
+			InflectionType: "五段・ラ行", InflectionForm: formBase, BaseForm: "ある",
+		}
+		result = append(result, auxDa("で", formContinuative), particleWa())
+		if polite {
+			negative, _ := politeVerb(aru, p.Tense, p.Polarity)
+			result = append(result, negative...)
+		} else if p.Tense == TensePast {
+			result = append(result, adjectiveNai("なかっ", formTa), auxTa(false))
+		} else {
+			result = append(result, adjectiveNai("ない", formBase))
+		}
+	case p.Copula == CopulaWritten:
+		// 学生である, 学生であります
+		var aru []MorphemeInfo
+		if polite {
+			aru, _ = politeVerb(auxAru("ある", formBase), p.Tense, p.Polarity)
+		} else {
+			aru, _ = casualVerb(auxAru("ある", formBase), p.Tense, p.Polarity)
+		}
+		result = append(result, auxDa("で", formContinuative))
+		result = append(result, aru...)
+	case p.Tense == TensePast && polite:
+		result = append(result, auxDesu("でし", formContinuative), auxTa(false))
+	case p.Tense == TensePast:
+		result = append(result, auxDa("だっ", formTa), auxTa(false))
+	case p.Modality == ModalityConjecture:
+		// 雨だろう: the copula itself takes the conjecture
+	case use == copulaBare:
+	case use == copulaAttributive:
+		result = append(result, auxDa("な", "体言接続"))
+	case polite:
+		result = append(result, auxDesu("です", formBase))
+	default:
+		result = append(result, auxDa("だ", formBase))
+	}
+
+	if p.Modality == ModalityConjecture {
+		result = append(result, conjecture(p.Politeness)...)
+	}
+	return result
+}
+
+// inflect conjugates m into the form and appends the following morphemes.
+func inflect(m MorphemeInfo, form string, following ...MorphemeInfo) ([]MorphemeInfo, bool) {
+	conjugated, ok := conjugate(m, form)
+	if !ok {
+		return nil, false
+	}
+	return append([]MorphemeInfo{conjugated}, following...), true
+}
+
+// kamoshirenai returns かもしれない or かもしれません.
+func kamoshirenai(politeness Register) []MorphemeInfo {
+	kamo := MorphemeInfo{
+		Surface: "かも", PartOfSpeech: "助詞", PartOfSpeechDetail1: "副助詞",
+		PartOfSpeechDetail2: "*", PartOfSpeechDetail3: "*",
+		InflectionType: "*", InflectionForm: "*", BaseForm: "かも",
+	}
+	shireru := MorphemeInfo{
+		Surface: "しれる", PartOfSpeech: "動詞", PartOfSpeechDetail1: "自立",
+		PartOfSpeechDetail2: "*", PartOfSpeechDetail3: "*",
+		InflectionType: "一段", InflectionForm: formBase, BaseForm: "しれる",
+	}
+	var negative []MorphemeInfo
+	if politeness == RegisterPolite {
+		negative, _ = politeVerb(shireru, TenseNonPast, PolarityNegative)
+	} else {
+		negative, _ = inflect(shireru, formIrrealis, auxNai("ない", formBase))
+	}
+	return append([]MorphemeInfo{kamo}, negative...)
+}
+
+// conjecture returns だろう or でしょう.
+func conjecture(politeness Register) []MorphemeInfo {
+	if politeness == RegisterPolite {
+		return []MorphemeInfo{auxDesu("でしょ", formIrrealis), auxU()}
+	}
+	return []MorphemeInfo{auxDa("だろ", formIrrealis), auxU()}
+}
+
+// aspectVerb returns the auxiliary verb of the aspect in its base form.
+func aspectVerb(a Aspect) MorphemeInfo {
+	inflections := map[Aspect][2]string{
+		AspectProgressive: {"いる", "一段"},
+		AspectResultative: {"ある", "五段・ラ行"},
+		AspectPreparatory: {"おく", "五段・カ行イ音便"},
+		AspectCompletive:  {"しまう", "五段・ワ行促音便"},
+		AspectAway:        {"いく", "五段・カ行促音便"},
+		AspectToward:      {"くる", "カ変・クル"},
+		AspectTrial:       {"みる", "一段"},
+	}
+	v := inflections[a]
+	return MorphemeInfo{
+		Surface: v[0], PartOfSpeech: "動詞", PartOfSpeechDetail1: "非自立",
+		PartOfSpeechDetail2: "*", PartOfSpeechDetail3: "*",
+		InflectionType: v[1], InflectionForm: formBase, BaseForm: v[0],
+	}
+}
+
+// auxiliary returns an auxiliary verb morpheme.
+func auxiliary(surface, inflectionType, form, base string) MorphemeInfo {
+	return MorphemeInfo{
+		Surface: surface, PartOfSpeech: "助動詞", PartOfSpeechDetail1: "*",
+		PartOfSpeechDetail2: "*", PartOfSpeechDetail3: "*",
+		InflectionType: inflectionType, InflectionForm: form, BaseForm: base,
+	}
+}
+
+func auxMasu(surface, form string) MorphemeInfo {
+	return auxiliary(surface, "特殊・マス", form, "ます")
+}
+
+func auxDesu(surface, form string) MorphemeInfo {
+	return auxiliary(surface, "特殊・デス", form, "です")
+}
+
+func auxDa(surface, form string) MorphemeInfo {
+	return auxiliary(surface, "特殊・ダ", form, "だ")
+}
+
+func auxNai(surface, form string) MorphemeInfo {
+	return auxiliary(surface, "特殊・ナイ", form, "ない")
+}
+
+// adjectiveNai returns ない as IPADIC tokenizes it after ある and では: an
+// adjective rather than an auxiliary.
+func adjectiveNai(surface, form string) MorphemeInfo {
+	return MorphemeInfo{
+		Surface: surface, PartOfSpeech: "形容詞", PartOfSpeechDetail1: "自立",
+		PartOfSpeechDetail2: "*", PartOfSpeechDetail3: "*",
+		InflectionType: "形容詞・アウオ段", InflectionForm: form, BaseForm: "ない",
+	}
+}
+
+// auxAru returns the ある of である and じゃありません, which IPADIC tokenizes
+// as an auxiliary rather than a verb.
+func auxAru(surface, form string) MorphemeInfo {
+	return auxiliary(surface, "五段・ラ行アル", form, "ある")
+}
+
+func auxTai() MorphemeInfo {
+	return auxiliary("たい", "特殊・タイ", formBase, "たい")
+}
+
+func auxN() MorphemeInfo {
+	return auxiliary("ん", "不変化型", formBase, "ん")
+}
+
+func auxU() MorphemeInfo {
+	return auxiliary("う", "不変化型", formBase, "う")
+}
+
+// auxTa returns the past auxiliary た, or だ after 読ん and 泳い.
+func auxTa(voiced bool) MorphemeInfo {
+	if voiced {
+		return auxiliary("だ", "特殊・タ", formBase, "だ")
+	}
+	return auxiliary("た", "特殊・タ", formBase, "た")
+}
+
+// teParticle returns the conjunctive particle て, or で after 読ん and 泳い.
+func teParticle(voiced bool) MorphemeInfo {
+	surface := "て"
+	if voiced {
+		surface = "で"
+	}
+	return MorphemeInfo{
+		Surface: surface, PartOfSpeech: "助詞", PartOfSpeechDetail1: "接続助詞",
+		PartOfSpeechDetail2: "*", PartOfSpeechDetail3: "*",
+		InflectionType: "*", InflectionForm: "*", BaseForm: surface,
+	}
+}
+
+// particleJa returns the じゃ of じゃない.
+func particleJa() MorphemeInfo {
+	return MorphemeInfo{
+		Surface: "じゃ", PartOfSpeech: "助詞", PartOfSpeechDetail1: "副助詞",
+		PartOfSpeechDetail2: "*", PartOfSpeechDetail3: "*",
+		InflectionType: "*", InflectionForm: "*", BaseForm: "じゃ",
+	}
+}
+
+// nominalizer returns the explanatory の of 読むのだ.
+func nominalizer() MorphemeInfo {
+	return MorphemeInfo{
+		Surface: "の", PartOfSpeech: "名詞", PartOfSpeechDetail1: "非自立",
+		PartOfSpeechDetail2: "一般", PartOfSpeechDetail3: "*",
+		InflectionType: "*", InflectionForm: "*", BaseForm: "の",
+	}
+}
+
+// particleWa returns the binding particle は of ではない.
+func particleWa() MorphemeInfo {
+	return MorphemeInfo{
+		Surface: "は", PartOfSpeech: "助詞", PartOfSpeechDetail1: "係助詞",
+		PartOfSpeechDetail2: "*", PartOfSpeechDetail3: "*",
+		InflectionType: "*", InflectionForm: "*", BaseForm: "は",
+	}
+}
+
+// convertPredicate converts the main predicate of the sentence to the target
+// register by analysing it, flipping its politeness and generating it again in
+// the style. It reports false when there is no main predicate or when the
+// generator cannot reproduce the predicate as written (e.g. らしい, 読むんだ,
+// 読んでください), so that the rule-based converters can handle the sentence
+// instead.
+func convertPredicate(morphemes []MorphemeInfo, target Register, style Style) ([]MorphemeInfo, bool) {
+	p := analyzePredicate(morphemes)
+	if p == nil || p.Politeness == target || !allowsPoliteness(morphemes[p.End:]) {
+		return nil, false
+	}
+
+	// The source may be written in either style, e.g. 美しくないです
+	reproduced := false
+	for _, s := range []Style{style, {AdjectiveNegative: AdjectiveNegativeNaiDesu}} {
+		original, err := generatePredicate(*p, s)
+		reproduced = reproduced || (err == nil && surfaceOf(original) == surfaceOf(morphemes[p.HeadIndex:p.End]))
+	}
+	if !reproduced {
+		return nil, false
+	}
+
+	flipped := *p
+	flipped.Politeness = target
+	if target == RegisterCasual && p.Modality == ModalityVolitional {
+		// ましょう becomes the base form rather than the volitional (読みましょう → 読む)
+		flipped.Modality = ModalityNone
+	}
+	if target == RegisterPolite && p.Copula == CopulaWritten && style.Copula == CopulaDesu {
+		// である → です; であります is kept as である
+		flipped.Copula = CopulaPlain
+	}
+	generated, err := generatePredicate(flipped, style)
+	if err != nil {
+		return nil, false
+	}
+	inheritSpans(generated, morphemes[p.HeadIndex:p.End])
+
+	result := make([]MorphemeInfo, 0, len(morphemes)+len(generated))
+	result = append(result, morphemes[:p.HeadIndex]...)
+	result = append(result, generated...)
+	result = append(result, morphemes[p.End:]...)
+	return result, true
+}
+
+// allowsPoliteness reports whether the final particles after a predicate can
+// follow both registers. Particles such as ぞ, ぜ and the prohibitive な only
+// follow the casual register.
+func allowsPoliteness(trailing []MorphemeInfo) bool {
+	for _, m := range trailing {
+		if !isSentenceFinalParticle(m) {
+			continue
+		}
+		switch m.Surface {
+		case "か", "よ", "ね", "よね", "わ":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// surfaceOf returns the text of the morphemes.
+func surfaceOf(morphemes []MorphemeInfo) string {
+	var b strings.Builder
+	for _, m := range morphemes {
+		b.WriteString(m.Surface)
+	}
+	return b.String()
+}
@@ -0,0 +1,132 @@
+package kjconv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeneratePredicate(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		input  string   // 述語を含む文
+		polite []string // 敬体の形態素
+		casual []string // 常体の形態素
+	}{
+		{"本を読む。", []string{"読み", "ます"}, []string{"読む"}},
+		{"本を読んだ。", []string{"読み", "まし", "た"}, []string{"読ん", "だ"}},
+		{"本を読まなかった。", []string{"読み", "ませ", "ん", "でし", "た"}, []string{"読ま", "なかっ", "た"}},
+		{"本を読んでいる。", []string{"読ん", "で", "い", "ます"}, []string{"読ん", "で", "いる"}},
+		{"一緒に読もう。", []string{"読み", "ましょ", "う"}, []string{"読も", "う"}},
+		{"彼が来た。", []string{"来", "まし", "た"}, []string{"来", "た"}},
+		{"勉強しない。", []string{"し", "ませ", "ん"}, []string{"し", "ない"}},
+		{"お金がない。", []string{"ない", "です"}, []string{"ない"}},
+		{"お金がありません。", []string{"あり", "ませ", "ん"}, []string{"ない"}},
+		{"美しくなかった。", []string{"美しく", "あり", "ませ", "ん", "でし", "た"}, []string{"美しく", "なかっ", "た"}},
+		{"よかった。", []string{"よかっ", "た", "です"}, []string{"よかっ", "た"}},
+		{"静かではない。", []string{"静か", "で", "は", "あり", "ませ", "ん"}, []string{"静か", "で", "は", "ない"}},
+		{"学生だった。", []string{"学生", "でし", "た"}, []string{"学生", "だっ", "た"}},
+		{"明日は雨だろう。", []string{"雨", "でしょ", "う"}, []string{"雨", "だろ", "う"}},
+		{"本ですか。", []string{"本", "です"}, []string{"本"}},
+		{"元気ですか？", []string{"元気", "です"}, []string{"元気"}},
+		{"これは本である。", []string{"本", "で", "あり", "ます"}, []string{"本", "で", "ある"}},
+		{"これは本じゃない。", []string{"本", "じゃ", "あり", "ませ", "ん"}, []string{"本", "じゃ", "ない"}},
+		{"雨が降るかもしれない。", []string{"降る", "かも", "しれ", "ませ", "ん"}, []string{"降る", "かも", "しれ", "ない"}},
+		{"明日は雨かもしれない。", []string{"雨", "かも", "しれ", "ませ", "ん"}, []string{"雨", "かも", "しれ", "ない"}},
+		{"ケーキを食べたい。", []string{"食べ", "たい", "です"}, []string{"食べ", "たい"}},
+		{"食べたくなかった。", []string{"食べ", "たく", "あり", "ませ", "ん", "でし", "た"}, []string{"食べ", "たく", "なかっ", "た"}},
+		{"もう行くのだ。", []string{"行く", "の", "です"}, []string{"行く", "の", "だ"}},
+		{"彼は学生なのだ。", []string{"学生", "な", "の", "です"}, []string{"学生", "な", "の", "だ"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			p, err := converter.AnalyzePredicate(tt.input)
+			if err != nil {
+				t.Fatalf("AnalyzePredicate() failed: %v", err)
+			}
+			if p == nil {
+				t.Fatalf("AnalyzePredicate(%q) = nil", tt.input)
+			}
+
+			for _, target := range []Register{RegisterPolite, RegisterCasual} {
+				expected := tt.casual
+				if target == RegisterPolite {
+					expected = tt.polite
+				}
+				q := *p
+				q.Politeness = target
+				morphemes, err := GeneratePredicate(q)
+				if err != nil {
+					t.Fatalf("GeneratePredicate(%v) failed: %v", target, err)
+				}
+				var got []string
+				for _, m := range morphemes {
+					got = append(got, m.Surface)
+				}
+				if strings.Join(got, "|") != strings.Join(expected, "|") {
+					t.Errorf("GeneratePredicate(%v) = %q, expected %q", target, got, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratePredicate_Features(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	// 生成した形態素は、同じ文を形態素解析した結果と同じ素性を持つ
+	for _, input := range []string{
+		"読みませんでした", "泳いでしまった", "美しくありません", "静かではなかった", "食べましょう",
+		"静かでありました", "本じゃありませんでした", "降るかもしれません", "食べたくありません", "学生なのです",
+	} {
+		p, err := converter.AnalyzePredicate(input)
+		if err != nil {
+			t.Fatalf("AnalyzePredicate() failed: %v", err)
+		}
+		if p == nil {
+			t.Fatalf("AnalyzePredicate(%q) = nil", input)
+		}
+		generated, err := GeneratePredicate(*p)
+		if err != nil {
+			t.Fatalf("GeneratePredicate(%q) failed: %v", input, err)
+		}
+		analysed, err := converter.AnalyzeMorphemes(input)
+		if err != nil {
+			t.Fatalf("AnalyzeMorphemes() failed: %v", err)
+		}
+		if len(generated) != len(analysed) {
+			t.Fatalf("GeneratePredicate(%q) returned %d morphemes, expected %d", input, len(generated), len(analysed))
+		}
+		for i, m := range generated {
+			a := analysed[i]
+			if m.Surface != a.Surface || m.PartOfSpeech != a.PartOfSpeech || m.InflectionType != a.InflectionType ||
+				m.InflectionForm != a.InflectionForm || m.BaseForm != a.BaseForm {
+				t.Errorf("%q: morpheme %d = %+v, expected %+v", input, i, m, a)
+			}
+		}
+	}
+}
+
+func TestGeneratePredicate_Unsupported(t *testing.T) {
+	p := Predicate{
+		Head:     MorphemeInfo{Surface: "読ん", PartOfSpeech: "動詞", InflectionType: "五段・マ行", BaseForm: "読む"},
+		Class:    PredicateVerb,
+		Modality: ModalityRequest,
+	}
+	if _, err := GeneratePredicate(p); err == nil {
+		t.Error("GeneratePredicate() with request modality succeeded, expected an error")
+	}
+
+	p.Modality = ModalityVolitional
+	p.Polarity = PolarityNegative
+	if _, err := GeneratePredicate(p); err == nil {
+		t.Error("GeneratePredicate() with negative volitional succeeded, expected an error")
+	}
+}
@@ -7,4 +7,4 @@ require (
 	github.com/ikawaha/kagome/v2 v2.10.2
 )
 
-require github.com/ikawaha/kagome-dict v1.1.6 // indirect
+require github.com/ikawaha/kagome-dict v1.1.6
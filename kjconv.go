@@ -2,9 +2,14 @@
 package kjconv
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"regexp"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/ikawaha/kagome-dict/dict"
 	"github.com/ikawaha/kagome-dict/ipa"
 	"github.com/ikawaha/kagome/v2/tokenizer"
 )
@@ -17,52 +22,388 @@ const (
 	CasualToPolite ConversionMode = iota
 	// PoliteToCasual converts from polite form (敬体) to casual form (常体)
 	PoliteToCasual
+	// AutoUnify detects the dominant register of the text and converts the other
+	// sentences to it. Texts that mix registers more than the mixed threshold
+	// allows are rejected with ErrMixedStyle; see SetMixedThreshold.
+	AutoUnify
 )
 
+// DefaultMixedThreshold is the default share of the dominant register below
+// which AutoUnify considers a text mixed.
+const DefaultMixedThreshold = 0.6
+
+// ErrMixedStyle is returned by AutoUnify for texts without a clearly dominant register.
+var ErrMixedStyle = errors.New("mixed style")
+
 // Converter handles Japanese text style conversion.
 type Converter struct {
-	tokenizer *tokenizer.Tokenizer
+	tokenizer    *tokenizer.Tokenizer
+	dict         *dict.Dict
+	userDict     *dict.UserDict
+	tokenizeMode tokenizer.TokenizeMode
+	logger       *slog.Logger
+
+	nounEndingBody    NounEndingPolicy
+	nounEndingHeading NounEndingPolicy
+	terminators       Terminators
+	segmentation      Segmentation
+	protectedPatterns []*regexp.Regexp
+	quotePolicies     map[rune]QuotePolicy
+	rules             *RuleRegistry
+	disabledRules     map[string]bool
+	style             Style
+	mixedThreshold    float64
+	errorPolicy       ErrorPolicy
 }
 
-// NewConverter creates a new Converter instance with IPADIC dictionary.
-func NewConverter() (*Converter, error) {
-	t, err := tokenizer.New(ipa.Dict(), tokenizer.OmitBosEos())
+// NewConverter creates a new Converter instance. Without options it uses the
+// IPADIC dictionary and the default settings; see Option for the settings that
+// can be changed.
+func NewConverter(opts ...Option) (*Converter, error) {
+	c := &Converter{
+		dict:              ipa.Dict(),
+		tokenizeMode:      tokenizer.Normal,
+		logger:            slog.New(slog.DiscardHandler),
+		terminators:       DefaultTerminators(),
+		protectedPatterns: DefaultProtectedPatterns(),
+		mixedThreshold:    DefaultMixedThreshold,
+	}
+	c.rules = c.newRuleRegistry()
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+
+	tokenizerOpts := []tokenizer.Option{tokenizer.OmitBosEos()}
+	if c.userDict != nil {
+		tokenizerOpts = append(tokenizerOpts, tokenizer.UserDict(c.userDict))
+	}
+	t, err := tokenizer.New(c.dict, tokenizerOpts...)
 	if err != nil {
 		return nil, err
 	}
+	c.tokenizer = t
 	
-	return &Converter{
-		tokenizer: t,
-	}, nil
+	return c, nil
+}
+
+// Result holds the detailed outcome of ConvertDetailed.
+type Result struct {
+	Text      string           // 変換後のテキスト
+	Target    Register         // 変換先の文体（AutoUnify では検出した文体）
+	Sentences []SentenceResult // 文ごとの変換結果
+	Warnings  []Warning        // 全ての文の警告
+	Edits     []TextEdit       // 入力テキストに対する変更
+
+	alignment []TextEdit // 形態素ごとの変更（Edits は隣接するものをまとめたもの）
+}
+
+// SentenceResult describes how a single sentence was converted.
+type SentenceResult struct {
+	Original  string // 変換前の文
+	Converted string // 変換後の文
+
+	// Input and Output locate the sentence in the input text and in the
+	// converted text (Result.Text).
+	Input  Range
+	Output Range
+
+	Register Register   // 変換前の文体
+	Rules    []string   // 文を変更した変換ルールのID（RuleVerb など）
+	Edits    []TextEdit // 入力テキストに対する変更（位置は入力テキスト全体が基準）
+
+	// NounEnding is true when the sentence ends with a noun (体言止め), and
+	// NounEndingPolicy is the policy that was applied to it.
+	NounEnding       bool
+	NounEndingPolicy NounEndingPolicy
+
+	Warnings   []Warning  // この文の警告
+	SkipReason SkipReason // 変換しなかった理由
+}
+
+// SkipReason explains why a sentence was intentionally left unchanged.
+type SkipReason int
+
+const (
+	// SkipNone means the sentence was not skipped.
+	SkipNone SkipReason = iota
+	// SkipLiteraryEnding is used for sentences ending with a literary or classical
+	// (文語) inflection such as なり, べし, ごとし, ず or まい.
+	SkipLiteraryEnding
+	// SkipQuoted is used for sentences that are a quotation, or contain one under
+	// QuoteSkip, and are left unchanged by the quote policy.
+	SkipQuoted
+	// SkipTargetRegister is used for sentences already in the target register.
+	SkipTargetRegister
+	// SkipNounEnding is used for sentences ending with a noun (体言止め) that
+	// are left unchanged by the NounEndingPolicy.
+	SkipNounEnding
+	// SkipInversion is used for inverted sentences (倒置法).
+	SkipInversion
+	// SkipNoRuleMatched is used for sentences whose ending no rule could convert.
+	SkipNoRuleMatched
+	// SkipError is used under ErrorContinue for sentences that could not be
+	// converted because of an error.
+	SkipError
+)
+
+// Range is the position of a sentence in a text.
+type Range struct {
+	Start, End         int // バイト位置
+	RuneStart, RuneEnd int // 文字（rune）位置
+}
+
+// String returns a human readable name of the reason.
+func (r SkipReason) String() string {
+	switch r {
+	case SkipNone:
+		return "none"
+	case SkipLiteraryEnding:
+		return "literary-ending"
+	case SkipQuoted:
+		return "quoted"
+	case SkipTargetRegister:
+		return "target-register"
+	case SkipNounEnding:
+		return "noun-ending"
+	case SkipInversion:
+		return "inversion"
+	case SkipNoRuleMatched:
+		return "no-rule-matched"
+	case SkipError:
+		return "error"
+	default:
+		return "unknown"
+	}
 }
 
 // Convert converts the input text according to the specified mode.
+// Sentences that are already in the target style are passed through unchanged,
+// so converting an already converted text is a no-op:
+// Convert(Convert(x, m), m) == Convert(x, m).
+// Under ErrorContinue the text is returned together with the errors of the
+// sentences that could not be converted; see SetErrorPolicy.
 func (c *Converter) Convert(text string, mode ConversionMode) (string, error) {
-	// Split text into sentences
-	sentences := SplitSentences(text)
+	result, err := c.ConvertDetailed(text, mode)
+	if result == nil {
+		return "", err
+	}
+	return result.Text, err
+}
+
+// ConvertDetailed converts the input text like Convert and reports the result
+// of each sentence. Whitespace and line breaks between sentences are kept as
+// they are, so the parts of the text that are not converted stay byte-for-byte
+// identical to the input. URLs, e-mail addresses, inline code, file paths and
+// other protected spans are never rewritten; see SetProtectedPatterns.
+func (c *Converter) ConvertDetailed(text string, mode ConversionMode) (*Result, error) {
+	return c.ConvertDocument(c.ParseDocument(text), mode)
+}
+
+// convertText splits text into sentences and converts each of them. The edits
+// of the sentences are given against text.
+func (c *Converter) convertText(text string, mode ConversionMode) (string, []SentenceResult, error) {
+	var sentences []SentenceResult
+	var b strings.Builder
+	
+	for _, segment := range c.splitSegments(text) {
+		b.WriteString(segment.Leading)
+		if segment.Text != "" {
+			sr, err := c.convertSentence(segment.Text, mode)
+			if err != nil {
+				return "", nil, err
+			}
+			sr.Edits = shiftEdits(sr.Edits, segment.Offset)
+			sentences = append(sentences, sr)
+			b.WriteString(sr.Converted)
+		}
+		b.WriteString(segment.Trailing)
+	}
+	
+	return b.String(), sentences, nil
+}
+
+// convertSentence converts a single sentence according to the specified mode.
+// Terminators and decorations at the end of the sentence (emoji, （笑）) are kept
+// aside so that the predicate before them can be converted. The edits of the
+// result are given against the sentence.
+func (c *Converter) convertSentence(sentence string, mode ConversionMode) (SentenceResult, error) {
+	sr := SentenceResult{Original: sentence}
 	
-	var convertedSentences []string
+	sentence, quoteEdits, ok, err := c.convertQuotes(&sr, sentence, mode)
+	if err != nil {
+		return sr, err
+	}
+	if !ok {
+		sr.Converted = sentence
+		sr.SkipReason = SkipQuoted
+		return sr, nil
+	}
 	
-	for _, sentence := range sentences {
-		var converted string
-		var err error
-		
-		switch mode {
-		case CasualToPolite:
-			converted, err = c.convertCasualToPolite(sentence)
-		case PoliteToCasual:
-			converted, err = c.convertPoliteToCasual(sentence)
-		default:
-			return "", fmt.Errorf("unsupported conversion mode: %d", mode)
+	body, tail := c.terminators.cutDecoration(sentence)
+	if body == "" {
+		// Only terminators and decorations, e.g. ！？(^_^)
+		sr.Converted = sentence
+		sr.Edits = composeEdits(quoteEdits, nil, sentence)
+		return sr, nil
+	}
+	if tail == "" {
+		converted, edits, err := c.convertBody(&sr, sentence, mode)
+		sr.Converted = converted
+		sr.Edits = composeEdits(quoteEdits, edits, converted)
+		return sr, err
+	}
+	
+	// Convert the body with a plain 句点 in place of the tail, so that it is
+	// tokenized the same way as an ordinary sentence. A question keeps a ？ so
+	// that its predicate is still read as a question (元気です？ → 元気？).
+	terminator := "。"
+	if strings.ContainsAny(tail, "？?") {
+		terminator = "？"
+	}
+	converted, edits, err := c.convertBody(&sr, body+terminator, mode)
+	sr.Converted = strings.TrimSuffix(converted, terminator) + tail
+	sr.Edits = composeEdits(quoteEdits, cutTerminatorEdits(edits, len(body), converted, terminator), sr.Converted)
+	return sr, err
+}
+
+// cutTerminatorEdits returns the edits of a body converted with the terminator
+// appended as edits of the body alone, given the converted text. Edits that
+// reach the terminator are merged into one that ends at the end of the body.
+func cutTerminatorEdits(edits []TextEdit, bodyLen int, converted, terminator string) []TextEdit {
+	shift := 0 // 変換後のテキストでの位置のずれ
+	for i, e := range edits {
+		if e.End > bodyLen {
+			start := min(e.Start, bodyLen)
+			return append(edits[:i], TextEdit{
+				Start:   start,
+				End:     bodyLen,
+				NewText: strings.TrimSuffix(converted[start+shift:], terminator),
+			})
+		}
+		shift += len(e.NewText) - (e.End - e.Start)
+	}
+	return edits
+}
+
+// convertBody converts the body of a sentence and records how it was handled in sr.
+// It detects the current register of the sentence first and returns the sentence
+// as-is when it is already in the target register. Sentences whose structure
+// cannot be handled reliably are kept as-is and reported as warnings. The
+// changes are also returned as edits against the sentence.
+func (c *Converter) convertBody(sr *SentenceResult, sentence string, mode ConversionMode) (string, []TextEdit, error) {
+	var convert func(string, *firedRules) (string, []TextEdit, error)
+	switch mode {
+	case CasualToPolite:
+		convert = c.convertCasualToPolite
+	case PoliteToCasual:
+		convert = c.convertPoliteToCasual
+	default:
+		return sentence, nil, fmt.Errorf("%w: %d", ErrUnsupportedMode, mode)
+	}
+	
+	if IsQuotedText(sentence) {
+		// The content has already been converted under QuoteConvert
+		if open, _ := utf8.DecodeRuneInString(sentence); c.quotePolicy(open) != QuoteConvert {
+			sr.SkipReason = SkipQuoted
 		}
-		
-		if err != nil {
-			return "", err
+		return sentence, nil, nil
+	}
+	
+	morphemes, err := c.AnalyzeMorphemes(sentence)
+	if err != nil {
+		return sentence, nil, err
+	}
+	if !hasContent(morphemes) {
+		return sentence, nil, nil
+	}
+	
+	target := mode.targetRegister()
+	sr.Register = detectRegister(morphemes)
+	if sr.Register == target {
+		if len(sr.Rules) == 0 {
+			// Nothing was converted inside quotations either
+			sr.SkipReason = SkipTargetRegister
 		}
-		
-		convertedSentences = append(convertedSentences, converted)
+		return sentence, nil, nil
+	}
+	
+	// 文語の文末
+	if isLiteraryEnding(morphemes) {
+		sr.SkipReason = SkipLiteraryEnding
+		sr.addWarning(WarningLiteraryEnding)
+		return sentence, nil, nil
 	}
 	
-	// Join sentences back together
-	return strings.Join(convertedSentences, ""), nil
+	// 倒置法
+	if comma := invertedComma(morphemes); comma >= 0 {
+		// The predicate before the 読点 decides the register of the sentence
+		if detectRegister(morphemes[:comma]) == target {
+			sr.Register = target
+			sr.SkipReason = SkipTargetRegister
+			return sentence, nil, nil
+		}
+		sr.SkipReason = SkipInversion
+		sr.addWarning(WarningInversion)
+		return sentence, nil, nil
+	}
+	
+	// 体言止め
+	if isNounEnding(morphemes) {
+		sr.NounEnding = true
+		sr.NounEndingPolicy = c.nounEndingPolicy(sr.Original)
+		switch sr.NounEndingPolicy {
+		case NounEndingAppend:
+			converted, alignment := c.reconstructSentence(sentence, appendCopula(morphemes, target))
+			return converted, alignment, nil
+		case NounEndingWarn:
+			sr.addWarning(WarningNounEnding)
+		}
+		sr.SkipReason = SkipNounEnding
+		return sentence, nil, nil
+	}
+	
+	fired := firedRules(sr.Rules)
+	converted, edits, err := convert(sentence, &fired)
+	if err != nil {
+		return sentence, nil, err
+	}
+	sr.Rules = fired
+	if converted == sentence {
+		sr.SkipReason = SkipNoRuleMatched
+		// Sentences without a predicate, such as え… and ありがとう, have
+		// nothing to convert and are not worth a warning
+		if analyzePredicate(morphemes) != nil || detectRegister(morphemes) != RegisterUnknown {
+			sr.addWarning(WarningNoRuleMatched)
+		}
+	}
+	return converted, edits, nil
+}
+
+// addWarning records a warning for the sentence.
+func (sr *SentenceResult) addWarning(reason WarningReason) {
+	sr.Warnings = append(sr.Warnings, Warning{Reason: reason, Text: sr.Original})
+}
+
+// hasContent reports whether the morphemes contain anything but symbols.
+func hasContent(morphemes []MorphemeInfo) bool {
+	for _, m := range morphemes {
+		if m.PartOfSpeech != "記号" {
+			return true
+		}
+	}
+	return false
+}
+
+// SetMixedThreshold sets the share of the dominant register, between 0 and 1,
+// below which AutoUnify considers a text mixed. The default is
+// DefaultMixedThreshold. With 0 only texts with as many polite sentences as
+// casual ones are mixed.
+func (c *Converter) SetMixedThreshold(threshold float64) error {
+	if threshold < 0 || threshold > 1 {
+		return fmt.Errorf("mixed threshold out of range: %v", threshold)
+	}
+	c.mixedThreshold = threshold
+	return nil
 }
@@ -1,6 +1,8 @@
 package kjconv
 
 import (
+	"errors"
+	"strings"
 	"testing"
 )
 
@@ -38,6 +40,16 @@ func TestConvert_CasualToPolite(t *testing.T) {
 			input:    "本を読む。",
 			expected: "本を読みます。",
 		},
+		{
+			name:     "コピュラのない疑問文",
+			input:    "元気？",
+			expected: "元気です？",
+		},
+		{
+			name:     "名詞＋か",
+			input:    "これは本か。",
+			expected: "これは本ですか。",
+		},
 		{
 			name:     "形容詞 → です",
 			input:    "この花は美しい。",
@@ -83,6 +95,11 @@ func TestConvert_CasualToPolite(t *testing.T) {
 			input:    "彼は『本を読む』と言った。",
 			expected: "彼は『本を読む』と言いました。",
 		},
+		{
+			name:     "複数文の引用文は変換しない",
+			input:    "彼は「今日は晴れだ。明日は雨だ。」と言った。",
+			expected: "彼は「今日は晴れだ。明日は雨だ。」と言いました。",
+		},
 		{
 			name:     "接続詞だから",
 			input:    "だから今日は晴れだ。",
@@ -160,6 +177,76 @@ func TestConvert_PoliteToCasual(t *testing.T) {
 			input:    "ですが今日は晴れです。",
 			expected: "だが今日は晴れだ。",
 		},
+		{
+			name:     "ませんでした → なかった",
+			input:    "本を読みませんでした。",
+			expected: "本を読まなかった。",
+		},
+		{
+			name:     "ましょう → 終止形",
+			input:    "一緒に読みましょう。",
+			expected: "一緒に読む。",
+		},
+		{
+			name:     "ではありませんでした → ではなかった",
+			input:    "静かではありませんでした。",
+			expected: "静かではなかった。",
+		},
+		{
+			name:     "名詞+ですか → 名詞+か",
+			input:    "本ですか。",
+			expected: "本か。",
+		},
+		{
+			name:     "形容動詞+ですか？ → 形容動詞+か？",
+			input:    "元気ですか？",
+			expected: "元気か？",
+		},
+		{
+			name:     "形容動詞+です？ → 形容動詞？",
+			input:    "元気です？",
+			expected: "元気？",
+		},
+		{
+			name:     "であります → である",
+			input:    "これは本であります。",
+			expected: "これは本である。",
+		},
+		{
+			name:     "じゃありません → じゃない",
+			input:    "これは本じゃありません。",
+			expected: "これは本じゃない。",
+		},
+		{
+			name:     "かもしれません → かもしれない",
+			input:    "雨が降るかもしれません。",
+			expected: "雨が降るかもしれない。",
+		},
+		{
+			name:     "たいです → たい",
+			input:    "ケーキを食べたいです。",
+			expected: "ケーキを食べたい。",
+		},
+		{
+			name:     "たくありませんでした → たくなかった",
+			input:    "行きたくありませんでした。",
+			expected: "行きたくなかった。",
+		},
+		{
+			name:     "のです → のだ",
+			input:    "もう行くのです。",
+			expected: "もう行くのだ。",
+		},
+		{
+			name:     "てください → て",
+			input:    "この本を読んでください。",
+			expected: "この本を読んで。",
+		},
+		{
+			name:     "らしいです → らしい",
+			input:    "雨が降るらしいです。",
+			expected: "雨が降るらしい。",
+		},
 	}
 
 	for _, tt := range tests {
@@ -202,3 +289,388 @@ func TestConvert_EmptyString(t *testing.T) {
 		t.Errorf("Convert() with empty string = %q, expected empty string", result)
 	}
 }
+
+func TestConvert_SkipTargetRegister(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		mode     ConversionMode
+		expected string
+	}{
+		{
+			name:     "敬体混じりの文章を敬体へ",
+			input:    "今日は晴れです。本を読む。",
+			mode:     CasualToPolite,
+			expected: "今日は晴れです。本を読みます。",
+		},
+		{
+			name:     "常体混じりの文章を常体へ",
+			input:    "今日は晴れだ。本を読みます。",
+			mode:     PoliteToCasual,
+			expected: "今日は晴れだ。本を読む。",
+		},
+		{
+			name:     "ますがは変換しない",
+			input:    "行きますが、雨だ。",
+			mode:     CasualToPolite,
+			expected: "行きますが、雨です。",
+		},
+		{
+			name:     "ですがは変換しない",
+			input:    "望ましいですが、使用している。",
+			mode:     CasualToPolite,
+			expected: "望ましいですが、使用しています。",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := converter.Convert(tt.input, tt.mode)
+			if err != nil {
+				t.Errorf("Convert() failed: %v", err)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("Convert() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvert_Idempotent(t *testing.T) {
+	inputs := []string{
+		"今日は晴れだ。本を読む。",
+		"今日は晴れです。本を読みます。",
+		"だから今日は晴れだ。",
+		"ですから今日は晴れです。",
+		"彼は「今日は晴れだ」と言った。",
+		"本を読まなかった。",
+		"プロセッサーはオプションだが、いくつかは推奨だ。",
+		"プロセッサーはオプションですが、いくつかは推奨です。",
+		"コンポーネントが設定されているが、config節で定義されていない場合、そのコンポーネントは有効にならない。",
+		"行きますが、雨だ。",
+		"晴れ。",
+		"元気です？",
+		"元気？",
+		"本ですか。",
+	}
+
+	// Under NounEndingAppend a casual question without a copula (元気？) must
+	// not be taken for a noun ending
+	for _, opts := range [][]Option{nil, {WithNounEndingPolicy(NounEndingAppend, NounEndingAppend)}} {
+		converter, err := NewConverter(opts...)
+		if err != nil {
+			t.Fatalf("NewConverter() failed: %v", err)
+		}
+		for _, mode := range []ConversionMode{CasualToPolite, PoliteToCasual} {
+			for _, input := range inputs {
+				once, err := converter.Convert(input, mode)
+				if err != nil {
+					t.Errorf("Convert(%q) failed: %v", input, err)
+					continue
+				}
+				twice, err := converter.Convert(once, mode)
+				if err != nil {
+					t.Errorf("Convert(%q) failed: %v", once, err)
+					continue
+				}
+				if once != twice {
+					t.Errorf("Convert is not idempotent for %q (mode %d): %q -> %q", input, mode, once, twice)
+				}
+			}
+		}
+	}
+}
+
+func TestConvert_PreservesLayout(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		mode     ConversionMode
+		expected string
+	}{
+		{
+			name:     "段落と字下げ",
+			input:    "　今日は晴れだ。\n\n　本を読む。\n",
+			mode:     CasualToPolite,
+			expected: "　今日は晴れです。\n\n　本を読みます。\n",
+		},
+		{
+			name:     "CRLF",
+			input:    "今日は晴れです。\r\n本を読みます。\r\n",
+			mode:     PoliteToCasual,
+			expected: "今日は晴れだ。\r\n本を読む。\r\n",
+		},
+		{
+			name:     "文間の空白",
+			input:    "今日は晴れだ。  本を読む。",
+			mode:     CasualToPolite,
+			expected: "今日は晴れです。  本を読みます。",
+		},
+		{
+			name:     "変換対象がなければ入力のまま",
+			input:    "\t今日は晴れです。 \r\n  本を読みます。\n\n",
+			mode:     CasualToPolite,
+			expected: "\t今日は晴れです。 \r\n  本を読みます。\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := converter.Convert(tt.input, tt.mode)
+			if err != nil {
+				t.Errorf("Convert() failed: %v", err)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("Convert() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertDetailed_WrappedSentence(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	// A line end without punctuation does not end a sentence by default
+	result, err := converter.ConvertDetailed("今日は\n晴れだ。", CasualToPolite)
+	if err != nil {
+		t.Fatalf("ConvertDetailed() failed: %v", err)
+	}
+	if len(result.Sentences) != 1 || result.Sentences[0].Converted != "今日は\n晴れです。" {
+		t.Errorf("ConvertDetailed().Sentences = %+v, expected one sentence %q", result.Sentences, "今日は\n晴れです。")
+	}
+}
+
+func TestConvert_LineSegmentation(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+	converter.SetTerminators(Terminators{Punctuation: "。"})
+
+	input := "- 本を読む\n- 字を書く\n\n明日は雨だ"
+
+	result, err := converter.Convert(input, CasualToPolite)
+	if err != nil {
+		t.Fatalf("Convert() failed: %v", err)
+	}
+	// Without LineBreak only the blank line between the paragraphs ends a sentence
+	if expected := "- 本を読む\n- 字を書きます\n\n明日は雨です"; result != expected {
+		t.Errorf("Convert() in sentence mode = %q, expected %q", result, expected)
+	}
+
+	converter.SetSegmentation(SegmentLines)
+	result, err = converter.Convert(input, CasualToPolite)
+	if err != nil {
+		t.Fatalf("Convert() failed: %v", err)
+	}
+	if expected := "- 本を読みます\n- 字を書きます\n\n明日は雨です"; result != expected {
+		t.Errorf("Convert() in line mode = %q, expected %q", result, expected)
+	}
+
+	// Each line is still split at its terminators
+	converter.SetTerminators(DefaultTerminators())
+	input = "行く。来る！\n  帰る\n"
+	detailed, err := converter.ConvertDetailed(input, CasualToPolite)
+	if err != nil {
+		t.Fatalf("ConvertDetailed() failed: %v", err)
+	}
+	if expected := "行きます。来ます！\n  帰ります\n"; detailed.Text != expected || len(detailed.Sentences) != 3 {
+		t.Errorf("ConvertDetailed() in line mode = %q in %d sentences, expected %q in 3", detailed.Text, len(detailed.Sentences), expected)
+	}
+	if applied, err := ApplyEdits(input, detailed.Edits); err != nil || applied != detailed.Text {
+		t.Errorf("ApplyEdits() = %q, %v, expected %q", applied, err, detailed.Text)
+	}
+}
+
+func TestConvertDetailed_Sentences(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	input := "今日は晴れだ。 本を読みます。\n美しい花。行くよ、明日は。「雨だ」"
+	result, err := converter.ConvertDetailed(input, CasualToPolite)
+	if err != nil {
+		t.Fatalf("ConvertDetailed() failed: %v", err)
+	}
+
+	expected := []struct {
+		converted string
+		register  Register
+		rules     string
+		skip      SkipReason
+	}{
+		{"今日は晴れです。", RegisterCasual, "predicate", SkipNone},
+		{"本を読みます。", RegisterPolite, "", SkipTargetRegister},
+		{"美しい花。", RegisterUnknown, "", SkipNounEnding},
+		{"行くよ、明日は。", RegisterUnknown, "", SkipInversion},
+		{"「雨だ」", RegisterUnknown, "", SkipQuoted},
+	}
+	if len(result.Sentences) != len(expected) {
+		t.Fatalf("ConvertDetailed() returned %d sentences, expected %d", len(result.Sentences), len(expected))
+	}
+
+	for i, sr := range result.Sentences {
+		e := expected[i]
+		if sr.Converted != e.converted || sr.Register != e.register || strings.Join(sr.Rules, ",") != e.rules || sr.SkipReason != e.skip {
+			t.Errorf("sentence %d = {%q %v %v %v}, expected {%q %v %v %v}", i,
+				sr.Converted, sr.Register, sr.Rules, sr.SkipReason, e.converted, e.register, e.rules, e.skip)
+		}
+
+		// The ranges locate the sentence in the input and in the output
+		if got := input[sr.Input.Start:sr.Input.End]; got != sr.Original {
+			t.Errorf("sentence %d: input[%d:%d] = %q, expected %q", i, sr.Input.Start, sr.Input.End, got, sr.Original)
+		}
+		if got := result.Text[sr.Output.Start:sr.Output.End]; got != sr.Converted {
+			t.Errorf("sentence %d: output[%d:%d] = %q, expected %q", i, sr.Output.Start, sr.Output.End, got, sr.Converted)
+		}
+		if got := string([]rune(input)[sr.Input.RuneStart:sr.Input.RuneEnd]); got != sr.Original {
+			t.Errorf("sentence %d: input runes [%d:%d] = %q, expected %q", i, sr.Input.RuneStart, sr.Input.RuneEnd, got, sr.Original)
+		}
+		if got := string([]rune(result.Text)[sr.Output.RuneStart:sr.Output.RuneEnd]); got != sr.Converted {
+			t.Errorf("sentence %d: output runes [%d:%d] = %q, expected %q", i, sr.Output.RuneStart, sr.Output.RuneEnd, got, sr.Converted)
+		}
+	}
+}
+
+func TestConvertDetailed_Rules(t *testing.T) {
+	converter, err := NewConverter(WithDisabledRules(RulePredicate))
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	result, err := converter.ConvertDetailed("だから本を読む。", CasualToPolite)
+	if err != nil {
+		t.Fatalf("ConvertDetailed() failed: %v", err)
+	}
+	if got := strings.Join(result.Sentences[0].Rules, ","); got != "verb,conjunction" {
+		t.Errorf("Rules = %q, expected %q", got, "verb,conjunction")
+	}
+
+	result, err = converter.ConvertDetailed("彼は天才なり。", CasualToPolite)
+	if err != nil {
+		t.Fatalf("ConvertDetailed() failed: %v", err)
+	}
+	if len(result.Sentences[0].Rules) != 0 {
+		t.Errorf("Rules = %q, expected none", result.Sentences[0].Rules)
+	}
+}
+
+func TestConvert_AutoUnify(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		target   Register
+	}{
+		{
+			name:     "mostly polite",
+			input:    "本を読みます。字を書く。空が青いです。",
+			expected: "本を読みます。字を書きます。空が青いです。",
+			target:   RegisterPolite,
+		},
+		{
+			name:     "mostly casual",
+			input:    "本を読む。字を書きます。空が青い。美しい花。",
+			expected: "本を読む。字を書く。空が青い。美しい花。",
+			target:   RegisterCasual,
+		},
+		{
+			name:     "no register",
+			input:    "美しい花。",
+			expected: "美しい花。",
+			target:   RegisterUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := converter.ConvertDetailed(tt.input, AutoUnify)
+			if err != nil {
+				t.Fatalf("ConvertDetailed() failed: %v", err)
+			}
+			if result.Text != tt.expected || result.Target != tt.target {
+				t.Errorf("ConvertDetailed() = %q (%v), expected %q (%v)", result.Text, result.Target, tt.expected, tt.target)
+			}
+		})
+	}
+}
+
+func TestConvert_AutoUnifyMixed(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	input := "本を読みます。字を書く。空が青いです。"
+	if err := converter.SetMixedThreshold(0.8); err != nil {
+		t.Fatalf("SetMixedThreshold() failed: %v", err)
+	}
+	if _, err := converter.Convert(input, AutoUnify); !errors.Is(err, ErrMixedStyle) {
+		t.Errorf("Convert() error = %v, expected %v", err, ErrMixedStyle)
+	}
+
+	if err := converter.SetMixedThreshold(1.5); err == nil {
+		t.Error("SetMixedThreshold(1.5) succeeded, expected an error")
+	}
+}
+
+func TestNegativeRule_Features(t *testing.T) {
+	// 否定のルールが生成した形態素は、変換後の文を形態素解析した結果と同じ素性を持つ
+	tests := []struct {
+		style    Style
+		input    string
+		expected string
+	}{
+		{Style{AdjectiveNegative: AdjectiveNegativeNaiDesu}, "美しくない", "美しくないです"},
+		{Style{AdjectiveNegative: AdjectiveNegativeNaiDesu}, "美しくなかった", "美しくなかったです"},
+		{Style{}, "美しくない", "美しくありません"},
+		{Style{}, "美しくなかった", "美しくありませんでした"},
+	}
+
+	for _, tt := range tests {
+		converter, err := NewConverter(WithStyle(tt.style))
+		if err != nil {
+			t.Fatalf("NewConverter() failed: %v", err)
+		}
+		morphemes, err := converter.AnalyzeMorphemes(tt.input)
+		if err != nil {
+			t.Fatalf("AnalyzeMorphemes() failed: %v", err)
+		}
+		converted := converter.handleNegativeCasualToPolite(morphemes)
+		analysed, err := converter.AnalyzeMorphemes(tt.expected)
+		if err != nil {
+			t.Fatalf("AnalyzeMorphemes() failed: %v", err)
+		}
+		if len(converted) != len(analysed) {
+			t.Fatalf("handleNegativeCasualToPolite(%q) returned %d morphemes, expected %d", tt.input, len(converted), len(analysed))
+		}
+		for i, m := range converted {
+			a := analysed[i]
+			if m.Surface != a.Surface || m.PartOfSpeech != a.PartOfSpeech || m.InflectionType != a.InflectionType ||
+				m.InflectionForm != a.InflectionForm || m.BaseForm != a.BaseForm {
+				t.Errorf("handleNegativeCasualToPolite(%q)[%d] = %+v, expected %+v", tt.input, i, m, a)
+			}
+		}
+	}
+}
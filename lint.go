@@ -0,0 +1,116 @@
+package kjconv
+
+import (
+	"unicode/utf8"
+)
+
+// LintIssue reports a sentence whose register differs from the expected one.
+type LintIssue struct {
+	Text   string // 文
+	Input  Range  // 入力中の位置
+	Line   int    // 行番号（1始まり）
+	Column int    // 行頭からの文字位置（1始まり）
+
+	Style      WritingStyle // 検出された文体
+	Confidence float64      // 検出の確信度
+	Expected   Register     // 期待される文体（多数派がない場合は RegisterUnknown）
+
+	// Suggestion is the sentence converted to the expected register, or an
+	// empty string when the converter could not convert it.
+	Suggestion string
+}
+
+// Lint reports every sentence whose register differs from expected. When
+// expected is RegisterUnknown, the dominant register of the text is expected.
+// When the text has no dominant register, as in an evenly mixed text, every
+// sentence with a register is reported with Expected RegisterUnknown and no
+// suggestion. Sentences that fit any register, such as 体言止め, are never
+// reported.
+func (c *Converter) Lint(text string, expected Register) ([]LintIssue, error) {
+	report, err := c.DetectStyle(text)
+	if err != nil {
+		return nil, err
+	}
+	if expected == RegisterUnknown {
+		expected = report.DominantRegister()
+	}
+
+	var mode ConversionMode
+	switch expected {
+	case RegisterPolite:
+		mode = CasualToPolite
+	case RegisterCasual:
+		mode = PoliteToCasual
+	}
+
+	// Convert the whole text once and take the suggestions from it, so that
+	// each sentence is split and converted exactly as Convert would
+	var converted *Result
+	suggestion := func(start int) string {
+		if converted == nil {
+			return ""
+		}
+		for _, sr := range converted.Sentences {
+			if sr.Input.Start == start && sr.Converted != sr.Original {
+				return sr.Converted
+			}
+		}
+		return ""
+	}
+
+	var issues []LintIssue
+	positions := newLineCounter(text)
+	for _, s := range report.Sentences {
+		if r := s.Style.Register(); r == RegisterUnknown || r == expected {
+			continue
+		}
+		if converted == nil && expected != RegisterUnknown {
+			if converted, err = c.ConvertDetailed(text, mode); err != nil {
+				return nil, err
+			}
+		}
+
+		line, column := positions.position(s.Input.Start)
+		issues = append(issues, LintIssue{
+			Text:       s.Text,
+			Input:      s.Input,
+			Line:       line,
+			Column:     column,
+			Style:      s.Style,
+			Confidence: s.Confidence,
+			Expected:   expected,
+			Suggestion: suggestion(s.Input.Start),
+		})
+	}
+	return issues, nil
+}
+
+// lineCounter converts byte offsets in a text to line and column numbers.
+// Offsets must be passed in increasing order, so that the text is scanned
+// only once.
+type lineCounter struct {
+	text      string
+	offset    int // 数え終えた位置（バイト）
+	line      int // offset の行番号
+	lineStart int // offset の行の開始位置（バイト）
+}
+
+// newLineCounter returns a lineCounter for the text.
+func newLineCounter(text string) *lineCounter {
+	return &lineCounter{text: text, line: 1}
+}
+
+// position returns the 1-based line and column (in runes) of the byte offset.
+func (lc *lineCounter) position(offset int) (int, int) {
+	if offset < lc.offset {
+		lc.offset, lc.line, lc.lineStart = 0, 1, 0
+	}
+	for i := lc.offset; i < offset; i++ {
+		if lc.text[i] == '\n' {
+			lc.line++
+			lc.lineStart = i + 1
+		}
+	}
+	lc.offset = offset
+	return lc.line, utf8.RuneCountInString(lc.text[lc.lineStart:offset]) + 1
+}
@@ -0,0 +1,74 @@
+package kjconv
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected Register
+		issues   []string // "行:列 文体 修正案"
+	}{
+		{
+			name:     "majority",
+			input:    "本を読みます。\n字を書く。空が青いです。",
+			expected: RegisterUnknown,
+			issues:   []string{"2:1 da 字を書きます。"},
+		},
+		{
+			name:     "required style",
+			input:    "本を読みます。\n  字を書く。空が青いです。",
+			expected: RegisterCasual,
+			issues:   []string{"1:1 polite 本を読む。", "2:8 polite 空が青い。"},
+		},
+		{
+			name:     "noun endings and quotations are not reported",
+			input:    "本を読みます。美しい花。「雨だ」",
+			expected: RegisterUnknown,
+		},
+		{
+			name:     "no majority",
+			input:    "本を読みます。字を書く。",
+			expected: RegisterUnknown,
+			issues:   []string{"1:1 polite ", "1:8 da "},
+		},
+		{
+			name:     "requests are polite",
+			input:    "本を読みます。本を読んでください。早く寝なさい。",
+			expected: RegisterPolite,
+		},
+		{
+			name:     "no register",
+			input:    "美しい花。青い空。",
+			expected: RegisterUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues, err := converter.Lint(tt.input, tt.expected)
+			if err != nil {
+				t.Fatalf("Lint() failed: %v", err)
+			}
+
+			var got []string
+			for _, issue := range issues {
+				got = append(got, fmt.Sprintf("%d:%d %v %s", issue.Line, issue.Column, issue.Style, issue.Suggestion))
+				if text := tt.input[issue.Input.Start:issue.Input.End]; text != issue.Text {
+					t.Errorf("input[%d:%d] = %q, expected %q", issue.Input.Start, issue.Input.End, text, issue.Text)
+				}
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.issues) {
+				t.Errorf("Lint() = %q, expected %q", got, tt.issues)
+			}
+		})
+	}
+}
@@ -0,0 +1,28 @@
+package kjconv
+
+import (
+	"strings"
+)
+
+// isLiteraryEnding reports whether the sentence ends with a literary or classical
+// inflection. IPADIC marks classical inflection types as 文語・〜 (文語・ナリ,
+// 文語・ベシ, 文語・ゴトシ...) and ラ変 (あり); the negative ず/ぬ and まい are
+// also treated as literary because they have no mechanical polite counterpart.
+func isLiteraryEnding(morphemes []MorphemeInfo) bool {
+	i := len(morphemes) - 1
+	for i >= 0 && (morphemes[i].PartOfSpeech == "記号" || isSentenceFinalParticle(morphemes[i])) {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	return isLiteraryInflection(morphemes[i])
+}
+
+// isLiteraryInflection reports whether the morpheme has a literary or classical inflection.
+func isLiteraryInflection(m MorphemeInfo) bool {
+	if strings.HasPrefix(m.InflectionType, "文語") || m.InflectionType == "ラ変" {
+		return true
+	}
+	return m.PartOfSpeech == "助動詞" && (m.BaseForm == "ぬ" || m.BaseForm == "まい")
+}
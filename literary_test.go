@@ -0,0 +1,78 @@
+package kjconv
+
+import (
+	"testing"
+)
+
+func TestConvert_LiteraryEnding(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "なり", input: "人は城なり。"},
+		{name: "べし", input: "急ぐべし。"},
+		{name: "ごとし", input: "光陰矢のごとし。"},
+		{name: "ず", input: "行かず。"},
+		{name: "まい", input: "二度と行くまい。"},
+		{name: "けり", input: "聞きけり。"},
+		{name: "ラ変", input: "我あり。"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := converter.ConvertDetailed(tt.input, CasualToPolite)
+			if err != nil {
+				t.Fatalf("ConvertDetailed() failed: %v", err)
+			}
+			if result.Text != tt.input {
+				t.Errorf("ConvertDetailed().Text = %q, expected %q", result.Text, tt.input)
+			}
+			if len(result.Sentences) != 1 {
+				t.Fatalf("ConvertDetailed() returned %d sentences, expected 1", len(result.Sentences))
+			}
+			if reason := result.Sentences[0].SkipReason; reason != SkipLiteraryEnding {
+				t.Errorf("SkipReason = %v, expected %v", reason, SkipLiteraryEnding)
+			}
+			if len(result.Warnings) != 1 || result.Warnings[0].Reason != WarningLiteraryEnding {
+				t.Errorf("Warnings = %+v, expected one %v warning", result.Warnings, WarningLiteraryEnding)
+			}
+		})
+	}
+}
+
+func TestIsLiteraryEnding(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{name: "文語・ナリ", input: "人は城なり。", expected: true},
+		{name: "文語・ベシ", input: "急ぐべし。", expected: true},
+		{name: "特殊・ヌ", input: "知らぬ。", expected: true},
+		{name: "口語の動詞", input: "本を読む。", expected: false},
+		{name: "口語の否定", input: "本を読まない。", expected: false},
+		{name: "だ", input: "今日は晴れだ。", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			morphemes, err := converter.AnalyzeMorphemes(tt.input)
+			if err != nil {
+				t.Fatalf("AnalyzeMorphemes() failed: %v", err)
+			}
+			if result := isLiteraryEnding(morphemes); result != tt.expected {
+				t.Errorf("isLiteraryEnding(%q) = %v, expected %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
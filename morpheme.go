@@ -14,11 +14,18 @@ type MorphemeInfo struct {
 	InflectionType string // 活用型
 	InflectionForm string // 活用形
 	BaseForm string // 原形
+
+	// start and end locate the morpheme in the analysed text (bytes). Both are
+	// zero for morphemes inserted by a conversion.
+	start, end int
 }
 
 // AnalyzeMorphemes performs morphological analysis on the input text.
+// Placeholders for protected spans (characters in the Private Use Area) are
+// reported as proper nouns, so that a sentence such as 詳細は<URL>だ is analysed
+// like one containing a name.
 func (c *Converter) AnalyzeMorphemes(text string) ([]MorphemeInfo, error) {
-	tokens := c.tokenizer.Tokenize(text)
+	tokens := c.tokenizer.Analyze(text, c.tokenizeMode)
 	
 	var morphemes []MorphemeInfo
 	for _, token := range tokens {
@@ -30,6 +37,8 @@ func (c *Converter) AnalyzeMorphemes(text string) ([]MorphemeInfo, error) {
 		
 		morpheme := MorphemeInfo{
 			Surface: token.Surface,
+			start:   token.Position,
+			end:     token.Position + len(token.Surface),
 		}
 		
 		// Extract features according to IPADIC format
@@ -55,6 +64,12 @@ func (c *Converter) AnalyzeMorphemes(text string) ([]MorphemeInfo, error) {
 			morpheme.BaseForm = features[6]
 		}
 		
+		if isPlaceholderText(morpheme.Surface) {
+			morpheme.PartOfSpeech = "名詞"
+			morpheme.PartOfSpeechDetail1 = "固有名詞"
+			morpheme.BaseForm = morpheme.Surface
+		}
+		
 		morphemes = append(morphemes, morpheme)
 	}
 	
@@ -0,0 +1,113 @@
+package kjconv
+
+import (
+	"strings"
+)
+
+// NounEndingPolicy decides how sentences ending with a noun (体言止め) are handled.
+type NounEndingPolicy int
+
+const (
+	// NounEndingLeave leaves the sentence unchanged. This is the default.
+	NounEndingLeave NounEndingPolicy = iota
+	// NounEndingAppend appends the copula of the target style (です or だ).
+	NounEndingAppend
+	// NounEndingWarn leaves the sentence unchanged and reports it.
+	NounEndingWarn
+)
+
+// String returns a human readable name of the policy.
+func (p NounEndingPolicy) String() string {
+	switch p {
+	case NounEndingAppend:
+		return "append"
+	case NounEndingWarn:
+		return "warn"
+	default:
+		return "leave"
+	}
+}
+
+// SetNounEndingPolicy sets the policies for noun-ended sentences (体言止め).
+// body applies to ordinary sentences and heading applies to headings and list
+// items, which typically want NounEndingLeave.
+func (c *Converter) SetNounEndingPolicy(body, heading NounEndingPolicy) {
+	c.nounEndingBody = body
+	c.nounEndingHeading = heading
+}
+
+// nounEndingPolicy returns the policy that applies to the sentence.
+func (c *Converter) nounEndingPolicy(sentence string) NounEndingPolicy {
+	if isHeadingLike(sentence) {
+		return c.nounEndingHeading
+	}
+	return c.nounEndingBody
+}
+
+// isNounEnding reports whether the sentence ends with a noun (体言止め).
+// Nouns inside a closing quotation or bracket, and nouns followed by a question
+// mark (元気？), are not counted.
+func isNounEnding(morphemes []MorphemeInfo) bool {
+	i := len(morphemes) - 1
+	for i >= 0 && morphemes[i].PartOfSpeech == "記号" {
+		if morphemes[i].PartOfSpeechDetail1 == "括弧閉" {
+			return false
+		}
+		if morphemes[i].Surface == "？" || morphemes[i].Surface == "?" {
+			// 元気？ is a casual question without a copula
+			return false
+		}
+		i--
+	}
+	return i >= 0 && morphemes[i].PartOfSpeech == "名詞"
+}
+
+// isHeadingLike reports whether the sentence looks like a heading or a list item,
+// that is, it starts with a list marker or does not end with a sentence terminator.
+func isHeadingLike(sentence string) bool {
+	s := strings.TrimSpace(sentence)
+	for _, marker := range []string{"#", "・", "･", "•", "-", "*", "+"} {
+		if strings.HasPrefix(s, marker) {
+			return true
+		}
+	}
+	return !strings.ContainsAny(lastRune(s), "。．.？！?!")
+}
+
+// lastRune returns the last character of s as a string.
+func lastRune(s string) string {
+	r := []rune(s)
+	if len(r) == 0 {
+		return ""
+	}
+	return string(r[len(r)-1])
+}
+
+// appendCopula appends the copula of the target register to a noun-ended sentence,
+// e.g. 晴れ。 → 晴れです。 (polite) or 晴れだ。 (casual).
+func appendCopula(morphemes []MorphemeInfo, target Register) []MorphemeInfo {
+	copula := MorphemeInfo{
+		Surface:        "だ",
+		PartOfSpeech:   "助動詞",
+		InflectionType: "特殊・ダ",
+		InflectionForm: "基本形",
+		BaseForm:       "だ",
+	}
+	if target == RegisterPolite {
+		copula.Surface = "です"
+		copula.InflectionType = "特殊・デス"
+		copula.BaseForm = "です"
+	}
+
+	// Insert the copula before trailing punctuation
+	i := len(morphemes)
+	for i > 0 && morphemes[i-1].PartOfSpeech == "記号" {
+		i--
+	}
+
+	result := make([]MorphemeInfo, 0, len(morphemes)+1)
+	result = append(result, morphemes[:i]...)
+	result = append(result, copula)
+	result = append(result, morphemes[i:]...)
+	return result
+}
@@ -0,0 +1,151 @@
+package kjconv
+
+import (
+	"testing"
+)
+
+func TestNounEndingPolicy(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           NounEndingPolicy
+		heading        NounEndingPolicy
+		input          string
+		mode           ConversionMode
+		expected       string
+		expectedPolicy NounEndingPolicy
+	}{
+		{
+			name:           "デフォルトは変換しない",
+			body:           NounEndingLeave,
+			heading:        NounEndingLeave,
+			input:          "今日は晴れ。",
+			mode:           CasualToPolite,
+			expected:       "今日は晴れ。",
+			expectedPolicy: NounEndingLeave,
+		},
+		{
+			name:           "本文にですを付加",
+			body:           NounEndingAppend,
+			heading:        NounEndingLeave,
+			input:          "今日は晴れ。",
+			mode:           CasualToPolite,
+			expected:       "今日は晴れです。",
+			expectedPolicy: NounEndingAppend,
+		},
+		{
+			name:           "本文にだを付加",
+			body:           NounEndingAppend,
+			heading:        NounEndingLeave,
+			input:          "今日は晴れ。",
+			mode:           PoliteToCasual,
+			expected:       "今日は晴れだ。",
+			expectedPolicy: NounEndingAppend,
+		},
+		{
+			name:           "見出しは変換しない",
+			body:           NounEndingAppend,
+			heading:        NounEndingLeave,
+			input:          "概要",
+			mode:           CasualToPolite,
+			expected:       "概要",
+			expectedPolicy: NounEndingLeave,
+		},
+		{
+			name:           "箇条書きは変換しない",
+			body:           NounEndingAppend,
+			heading:        NounEndingLeave,
+			input:          "・設定ファイルの場所。",
+			mode:           CasualToPolite,
+			expected:       "・設定ファイルの場所。",
+			expectedPolicy: NounEndingLeave,
+		},
+		{
+			name:           "警告",
+			body:           NounEndingWarn,
+			heading:        NounEndingLeave,
+			input:          "今日は晴れ。",
+			mode:           CasualToPolite,
+			expected:       "今日は晴れ。",
+			expectedPolicy: NounEndingWarn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := NewConverter()
+			if err != nil {
+				t.Fatalf("NewConverter() failed: %v", err)
+			}
+			converter.SetNounEndingPolicy(tt.body, tt.heading)
+
+			result, err := converter.ConvertDetailed(tt.input, tt.mode)
+			if err != nil {
+				t.Fatalf("ConvertDetailed() failed: %v", err)
+			}
+			if result.Text != tt.expected {
+				t.Errorf("ConvertDetailed().Text = %q, expected %q", result.Text, tt.expected)
+			}
+			if len(result.Sentences) != 1 {
+				t.Fatalf("ConvertDetailed() returned %d sentences, expected 1", len(result.Sentences))
+			}
+			sentence := result.Sentences[0]
+			if !sentence.NounEnding {
+				t.Errorf("NounEnding = false, expected true")
+			}
+			if sentence.NounEndingPolicy != tt.expectedPolicy {
+				t.Errorf("NounEndingPolicy = %v, expected %v", sentence.NounEndingPolicy, tt.expectedPolicy)
+			}
+		})
+	}
+}
+
+func TestIsNounEnding(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{
+			name:     "名詞で終わる文",
+			input:    "今日は晴れ。",
+			expected: true,
+		},
+		{
+			name:     "だで終わる文",
+			input:    "今日は晴れだ。",
+			expected: false,
+		},
+		{
+			name:     "動詞で終わる文",
+			input:    "本を読む。",
+			expected: false,
+		},
+		{
+			name:     "コピュラのない疑問文",
+			input:    "元気？",
+			expected: false,
+		},
+		{
+			name:     "引用で終わる文",
+			input:    "彼の『本』。",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			morphemes, err := converter.AnalyzeMorphemes(tt.input)
+			if err != nil {
+				t.Fatalf("AnalyzeMorphemes() failed: %v", err)
+			}
+			if result := isNounEnding(morphemes); result != tt.expected {
+				t.Errorf("isNounEnding(%q) = %v, expected %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package kjconv
+
+import (
+	"errors"
+	"log/slog"
+	"regexp"
+
+	"github.com/ikawaha/kagome-dict/dict"
+	"github.com/ikawaha/kagome/v2/tokenizer"
+)
+
+// Option configures a Converter created by NewConverter, e.g.
+//
+//	c, err := kjconv.NewConverter(
+//		kjconv.WithUserDictionary(userDict),
+//		kjconv.WithQuotePolicy(kjconv.QuoteConvert, '「'),
+//		kjconv.WithStyle(kjconv.Style{AdjectiveNegative: kjconv.AdjectiveNegativeNaiDesu}),
+//	)
+//
+// Most options have a setter on Converter with the same effect, so settings can
+// also be changed after the converter is created.
+type Option func(*Converter) error
+
+// WithDictionary sets the system dictionary of the tokenizer. The default is
+// IPADIC. The dictionary must have the IPADIC feature layout (品詞, 品詞細分類1-3,
+// 活用型, 活用形, 原形), as the conversion rules rely on it.
+func WithDictionary(d *dict.Dict) Option {
+	return func(c *Converter) error {
+		if d == nil {
+			return errors.New("dictionary is nil")
+		}
+		c.dict = d
+		return nil
+	}
+}
+
+// WithUserDictionary adds a user dictionary to the tokenizer, e.g. one loaded
+// with dict.NewUserDict for product names that must stay single nouns.
+func WithUserDictionary(d *dict.UserDict) Option {
+	return func(c *Converter) error {
+		c.userDict = d
+		return nil
+	}
+}
+
+// WithTokenizeMode sets the mode of the tokenizer. The default is tokenizer.Normal.
+func WithTokenizeMode(mode tokenizer.TokenizeMode) Option {
+	return func(c *Converter) error {
+		c.tokenizeMode = mode
+		return nil
+	}
+}
+
+// WithLogger sets the logger that receives debug messages about each sentence.
+// By default nothing is logged.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Converter) error {
+		if logger == nil {
+			logger = slog.New(slog.DiscardHandler)
+		}
+		c.logger = logger
+		return nil
+	}
+}
+
+// WithQuotePolicy sets the policy for quotations like SetQuotePolicy.
+func WithQuotePolicy(policy QuotePolicy, brackets ...rune) Option {
+	return func(c *Converter) error {
+		return c.SetQuotePolicy(policy, brackets...)
+	}
+}
+
+// WithProtectedPatterns replaces the patterns of protected spans like
+// SetProtectedPatterns.
+func WithProtectedPatterns(patterns ...*regexp.Regexp) Option {
+	return func(c *Converter) error {
+		c.SetProtectedPatterns(patterns)
+		return nil
+	}
+}
+
+// WithProtectedPattern adds a pattern of protected spans like AddProtectedPattern.
+func WithProtectedPattern(expr string) Option {
+	return func(c *Converter) error {
+		return c.AddProtectedPattern(expr)
+	}
+}
+
+// WithNounEndingPolicy sets the policies for noun-ended sentences like
+// SetNounEndingPolicy.
+func WithNounEndingPolicy(body, heading NounEndingPolicy) Option {
+	return func(c *Converter) error {
+		c.SetNounEndingPolicy(body, heading)
+		return nil
+	}
+}
+
+// WithSegmentation sets how text is divided into units like SetSegmentation.
+func WithSegmentation(s Segmentation) Option {
+	return func(c *Converter) error {
+		c.SetSegmentation(s)
+		return nil
+	}
+}
+
+// WithTerminators sets the sentence terminators like SetTerminators.
+func WithTerminators(t Terminators) Option {
+	return func(c *Converter) error {
+		c.SetTerminators(t)
+		return nil
+	}
+}
+
+// WithDisabledRules disables the conversion rules with the given IDs; see Rules.
+func WithDisabledRules(ids ...string) Option {
+	return func(c *Converter) error {
+		for _, id := range ids {
+			if err := c.SetRuleEnabled(id, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// WithEnabledRules enables the conversion rules with the given IDs again.
+func WithEnabledRules(ids ...string) Option {
+	return func(c *Converter) error {
+		for _, id := range ids {
+			if err := c.SetRuleEnabled(id, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// WithStyle sets the preferred polite expressions like SetStyle.
+func WithStyle(s Style) Option {
+	return func(c *Converter) error {
+		c.SetStyle(s)
+		return nil
+	}
+}
+
+// WithMixedThreshold sets the threshold of AutoUnify like SetMixedThreshold.
+func WithMixedThreshold(threshold float64) Option {
+	return func(c *Converter) error {
+		return c.SetMixedThreshold(threshold)
+	}
+}
+
+// WithErrorPolicy sets what a conversion does when a sentence cannot be
+// converted like SetErrorPolicy.
+func WithErrorPolicy(p ErrorPolicy) Option {
+	return func(c *Converter) error {
+		c.SetErrorPolicy(p)
+		return nil
+	}
+}
+
+// WithRule adds a conversion rule to the converter's RuleRegistry.
+func WithRule(rule Rule) Option {
+	return func(c *Converter) error {
+		return c.rules.Add(rule)
+	}
+}
@@ -0,0 +1,146 @@
+package kjconv
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewConverter_Options(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		mode     ConversionMode
+		input    string
+		expected string
+	}{
+		{
+			name:     "default style",
+			mode:     CasualToPolite,
+			input:    "この花は美しくない。彼は学者である。",
+			expected: "この花は美しくありません。彼は学者です。",
+		},
+		{
+			name:     "くないです and であります",
+			opts:     []Option{WithStyle(Style{AdjectiveNegative: AdjectiveNegativeNaiDesu, Copula: CopulaDearimasu})},
+			mode:     CasualToPolite,
+			input:    "この花は美しくない。彼は学者である。",
+			expected: "この花は美しくないです。彼は学者であります。",
+		},
+		{
+			name:     "くないです and であります to casual",
+			mode:     PoliteToCasual,
+			input:    "この花は美しくないです。彼は学者であります。",
+			expected: "この花は美しくない。彼は学者である。",
+		},
+		{
+			name:     "のである with であります",
+			opts:     []Option{WithStyle(Style{Copula: CopulaDearimasu})},
+			mode:     CasualToPolite,
+			input:    "彼は明日行くのである。",
+			expected: "彼は明日行くのであります。",
+		},
+		{
+			name:     "のである with であります by the copula rule",
+			opts:     []Option{WithStyle(Style{Copula: CopulaDearimasu}), WithDisabledRules(RulePredicate)},
+			mode:     CasualToPolite,
+			input:    "彼は明日行くのである。",
+			expected: "彼は明日行くのであります。",
+		},
+		{
+			name:     "のではない is not taken for のだ",
+			opts:     []Option{WithDisabledRules(RulePredicate)},
+			mode:     CasualToPolite,
+			input:    "彼は行くのではない。",
+			expected: "彼は行くのではない。",
+		},
+		{
+			name:     "くないです by the negative rule",
+			opts:     []Option{WithStyle(Style{AdjectiveNegative: AdjectiveNegativeNaiDesu}), WithDisabledRules(RulePredicate)},
+			mode:     CasualToPolite,
+			input:    "この花は美しくない。この花は美しくなかった。",
+			expected: "この花は美しくないです。この花は美しくなかったです。",
+		},
+		{
+			name:     "くありません by the negative rule",
+			opts:     []Option{WithDisabledRules(RulePredicate)},
+			mode:     CasualToPolite,
+			input:    "この花は美しくない。この花は美しくなかった。",
+			expected: "この花は美しくありません。この花は美しくありませんでした。",
+		},
+		{
+			name:     "quote policy",
+			opts:     []Option{WithQuotePolicy(QuoteConvert, '「')},
+			mode:     CasualToPolite,
+			input:    "「雨だ」と言った。",
+			expected: "「雨です」と言いました。",
+		},
+		{
+			name:     "protected pattern",
+			opts:     []Option{WithProtectedPattern(`[A-Z]+-\d+`)},
+			mode:     CasualToPolite,
+			input:    "原因はJIRA-12だ。",
+			expected: "原因はJIRA-12です。",
+		},
+		{
+			name:     "disabled rule",
+			opts:     []Option{WithDisabledRules(RuleConjunction)},
+			mode:     CasualToPolite,
+			input:    "だから雨だ。",
+			expected: "だから雨です。",
+		},
+		{
+			name:     "re-enabled rule",
+			opts:     []Option{WithDisabledRules(RuleConjunction), WithEnabledRules(RuleConjunction)},
+			mode:     CasualToPolite,
+			input:    "だから雨だ。",
+			expected: "ですから雨です。",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := NewConverter(tt.opts...)
+			if err != nil {
+				t.Fatalf("NewConverter() failed: %v", err)
+			}
+			result, err := converter.Convert(tt.input, tt.mode)
+			if err != nil {
+				t.Fatalf("Convert() failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Convert() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNewConverter_InvalidOptions(t *testing.T) {
+	for name, opt := range map[string]Option{
+		"unknown rule":    WithDisabledRules("no-such-rule"),
+		"invalid pattern": WithProtectedPattern("("),
+		"not a quote":     WithQuotePolicy(QuoteSkip, '（'),
+		"nil dictionary":  WithDictionary(nil),
+	} {
+		if _, err := NewConverter(opt); err == nil {
+			t.Errorf("NewConverter(%s) succeeded, expected an error", name)
+		}
+	}
+}
+
+func TestNewConverter_Logger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	converter, err := NewConverter(WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	if _, err := converter.Convert("本を読む。", CasualToPolite); err != nil {
+		t.Fatalf("Convert() failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "converted=本を読みます。") {
+		t.Errorf("log = %q, expected the converted sentence", buf.String())
+	}
+}
@@ -5,46 +5,45 @@ import (
 )
 
 // convertPoliteToCasual converts a sentence from polite form to casual form.
-func (c *Converter) convertPoliteToCasual(sentence string) (string, error) {
+// The IDs of the rules that changed the sentence are added to fired, and the
+// changes are returned as edits against the sentence.
+func (c *Converter) convertPoliteToCasual(sentence string, fired *firedRules) (string, []TextEdit, error) {
 	if IsQuotedText(sentence) {
 		// Don't convert quoted text
-		return sentence, nil
+		return sentence, nil, nil
 	}
 	
 	// Handle text with embedded quotes
 	if ContainsQuotedText(sentence) {
-		return ProcessTextWithQuotes(sentence, c.convertPoliteToCasualSegment)
+		return processTextWithQuotes(sentence, func(segment string) (string, []TextEdit, error) {
+			return c.convertPoliteToCasualSegment(segment, fired)
+		})
 	}
 	
-	return c.convertPoliteToCasualSegment(sentence)
+	return c.convertPoliteToCasualSegment(sentence, fired)
 }
 
 // convertPoliteToCasualSegment converts a text segment (without quotes) from polite to casual form.
-func (c *Converter) convertPoliteToCasualSegment(segment string) (string, error) {
+func (c *Converter) convertPoliteToCasualSegment(segment string, fired *firedRules) (string, []TextEdit, error) {
 	if strings.TrimSpace(segment) == "" {
-		return segment, nil
+		return segment, nil, nil
 	}
 	
 	morphemes, err := c.AnalyzeMorphemes(segment)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 	
 	if len(morphemes) == 0 {
-		return segment, nil
+		return segment, nil, nil
 	}
 	
-	// Convert from the end of the sentence
-	converted := c.convertVerbPoliteToCase(morphemes)
-	converted = c.convertAdjectivePoliteToCase(converted)
-	converted = c.convertNounPoliteToCase(converted)
-	converted = c.convertAuxiliaryPoliteToCase(converted)
-	converted = c.handleNegativePoliteToCase(converted)
-	converted = c.convertConjunctionPoliteToCase(converted)
+	// Apply the conversion rules in order of priority
+	converted := c.applyRules(morphemes, PoliteToCasual, fired)
 	
-	result := c.reconstructSentence(converted)
+	result, alignment := c.reconstructSentence(segment, converted)
 	
-	return result, nil
+	return result, alignment, nil
 }
 
 // convertVerbPoliteToCase converts verbs from polite to casual form.
@@ -277,7 +276,8 @@ func (c *Converter) getVerbNaiForm(morpheme MorphemeInfo) string {
 }
 
 // convertAdjectivePoliteToCase converts adjectives from polite to casual form.
-// ～いです → ～い, ～くありません → ～くない
+// ～いです → ～い, ～くありません → ～くない. Auxiliaries that inflect like an
+// adjective, such as らしい and たい, drop です the same way (降るらしいです → 降るらしい).
 func (c *Converter) convertAdjectivePoliteToCase(morphemes []MorphemeInfo) []MorphemeInfo {
 	if len(morphemes) == 0 {
 		return morphemes
@@ -299,7 +299,9 @@ func (c *Converter) convertAdjectivePoliteToCase(morphemes []MorphemeInfo) []Mor
 		// Handle いです → い
 		if last.Surface == "です" && actualLastIdx > 0 {
 			prev := result[actualLastIdx-1]
-			if prev.PartOfSpeech == "形容詞" && strings.HasSuffix(prev.Surface, "い") {
+			adjectival := prev.PartOfSpeech == "形容詞" || strings.HasPrefix(prev.InflectionType, "形容詞") ||
+				prev.InflectionType == "特殊・タイ"
+			if adjectival && strings.HasSuffix(prev.Surface, "い") {
 				// Remove です
 				result = result[:actualLastIdx]
 				// Add back any punctuation
@@ -339,6 +341,15 @@ func (c *Converter) convertNounPoliteToCase(morphemes []MorphemeInfo) []Morpheme
 	if actualLastIdx >= 0 {
 		last := result[actualLastIdx]
 		
+		// Handle であります → である
+		if last.Surface == "ます" && actualLastIdx > 1 &&
+		   result[actualLastIdx-1].PartOfSpeech == "助動詞" && result[actualLastIdx-1].Surface == "あり" &&
+		   result[actualLastIdx-2].Surface == "で" {
+			result[actualLastIdx-1].Surface = "ある"
+			result[actualLastIdx-1].InflectionForm = "基本形"
+			return append(result[:actualLastIdx], morphemes[actualLastIdx+1:]...)
+		}
+		
 		switch last.Surface {
 		case "です":
 			result[actualLastIdx].Surface = "だ"
@@ -393,28 +404,35 @@ func (c *Converter) convertAuxiliaryPoliteToCase(morphemes []MorphemeInfo) []Mor
 		}
 	}
 	
+	// Handle てください → て (読んでください → 読んで)
+	last := len(result) - 1
+	for last >= 0 && result[last].PartOfSpeech == "記号" {
+		last--
+	}
+	if last > 0 && isPoliteImperative(result[last]) && strings.HasSuffix(result[last].BaseForm, "さる") &&
+		result[last].BaseForm != "なさる" && isTeParticle(result[last-1]) {
+		result = append(result[:last], result[last+1:]...)
+	}
+	
 	return result
 }
 // convertConjunctionPoliteToCase converts conjunctions from polite to casual form.
-// ですから → だから, ですが → だが, ですが → が (when used as conjunction)
+// 文頭の接続詞 (ですから → だから, ですが → だが, ...) and
+// ですが → が (when used as conjunction)
 func (c *Converter) convertConjunctionPoliteToCase(morphemes []MorphemeInfo) []MorphemeInfo {
 	if len(morphemes) == 0 {
 		return morphemes
 	}
 	
-	result := make([]MorphemeInfo, len(morphemes))
-	copy(result, morphemes)
+	// Convert sentence-initial conjunctions
+	result := convertSentenceInitialConjunction(morphemes, RegisterCasual)
+	result = append([]MorphemeInfo(nil), result...)
 	
-	// Check all morphemes for conjunctions
+	// Check all morphemes for conjunctive particles
 	for i := 0; i < len(result); i++ {
 		morpheme := result[i]
 		
-		// Convert specific conjunctions
 		switch morpheme.Surface {
-		case "ですから":
-			if morpheme.PartOfSpeech == "接続詞" {
-				result[i].Surface = "だから"
-			}
 		case "ですが":
 			// Convert "ですが" to "だが" when it's used as a conjunction
 			if morpheme.PartOfSpeech == "接続詞" || (morpheme.PartOfSpeech == "助詞" && morpheme.PartOfSpeechDetail1 == "接続助詞") {
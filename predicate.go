@@ -0,0 +1,492 @@
+package kjconv
+
+import (
+	"strings"
+)
+
+// PredicateClass is the part of speech of a predicate.
+type PredicateClass int
+
+const (
+	// PredicateVerb is a verb, e.g. 読む.
+	PredicateVerb PredicateClass = iota
+	// PredicateAdjective is an i-adjective, e.g. 美しい.
+	PredicateAdjective
+	// PredicateNaAdjective is a na-adjective with a copula, e.g. 静かだ.
+	PredicateNaAdjective
+	// PredicateNounCopula is a noun with a copula, e.g. 学生だ.
+	PredicateNounCopula
+)
+
+// String returns a human readable name of the class.
+func (c PredicateClass) String() string {
+	switch c {
+	case PredicateVerb:
+		return "verb"
+	case PredicateAdjective:
+		return "adjective"
+	case PredicateNaAdjective:
+		return "na-adjective"
+	case PredicateNounCopula:
+		return "noun-copula"
+	default:
+		return "unknown"
+	}
+}
+
+// Tense is the tense of a predicate.
+type Tense int
+
+const (
+	// TenseNonPast is the present or future (読む, 読みます).
+	TenseNonPast Tense = iota
+	// TensePast is the past (読んだ, 読みました).
+	TensePast
+)
+
+// String returns a human readable name of the tense.
+func (t Tense) String() string {
+	switch t {
+	case TenseNonPast:
+		return "non-past"
+	case TensePast:
+		return "past"
+	default:
+		return "unknown"
+	}
+}
+
+// Polarity tells whether a predicate is negated.
+type Polarity int
+
+const (
+	// PolarityAffirmative is an affirmative predicate (読む).
+	PolarityAffirmative Polarity = iota
+	// PolarityNegative is a negated predicate (読まない, 読みません).
+	PolarityNegative
+)
+
+// String returns a human readable name of the polarity.
+func (p Polarity) String() string {
+	switch p {
+	case PolarityAffirmative:
+		return "affirmative"
+	case PolarityNegative:
+		return "negative"
+	default:
+		return "unknown"
+	}
+}
+
+// Modality is the attitude expressed at the end of a predicate.
+type Modality int
+
+const (
+	// ModalityNone is a plain statement.
+	ModalityNone Modality = iota
+	// ModalityVolitional is an intention or invitation (読もう, 読みましょう).
+	ModalityVolitional
+	// ModalityConjecture is a guess (読むだろう, 読むでしょう, 読むらしい, 降りそうだ).
+	ModalityConjecture
+	// ModalityQuestion is a question (読みますか, 読む？).
+	ModalityQuestion
+	// ModalityRequest is a request or command (読んでください, 読め).
+	ModalityRequest
+)
+
+// String returns a human readable name of the modality.
+func (m Modality) String() string {
+	switch m {
+	case ModalityNone:
+		return "none"
+	case ModalityVolitional:
+		return "volitional"
+	case ModalityConjecture:
+		return "conjecture"
+	case ModalityQuestion:
+		return "question"
+	case ModalityRequest:
+		return "request"
+	default:
+		return "unknown"
+	}
+}
+
+// Conjecture is the expression of a predicate with ModalityConjecture.
+type Conjecture int
+
+const (
+	// ConjectureDarou is だろう or でしょう (降るだろう).
+	ConjectureDarou Conjecture = iota
+	// ConjectureKamoshirenai is かもしれない or かもしれません (降るかもしれない).
+	ConjectureKamoshirenai
+	// ConjectureOther is any other conjecture, such as らしい, ようだ and そうだ.
+	ConjectureOther
+)
+
+// String returns a human readable name of the conjecture.
+func (c Conjecture) String() string {
+	switch c {
+	case ConjectureDarou:
+		return "darou"
+	case ConjectureKamoshirenai:
+		return "kamoshirenai"
+	case ConjectureOther:
+		return "other"
+	default:
+		return "unknown"
+	}
+}
+
+// CopulaForm is the form of the copula of a noun or na-adjective predicate,
+// and of the copula after the explanatory の.
+type CopulaForm int
+
+const (
+	// CopulaPlain is だ and です (学生だ, 学生ではない).
+	CopulaPlain CopulaForm = iota
+	// CopulaWritten is the written である and であります (学生である).
+	CopulaWritten
+	// CopulaContracted is the spoken じゃ of the negative (学生じゃない).
+	CopulaContracted
+)
+
+// String returns a human readable name of the form.
+func (f CopulaForm) String() string {
+	switch f {
+	case CopulaPlain:
+		return "plain"
+	case CopulaWritten:
+		return "written"
+	case CopulaContracted:
+		return "contracted"
+	default:
+		return "unknown"
+	}
+}
+
+// Aspect is an auxiliary verb attached with て, e.g. いる in 読んでいる.
+type Aspect int
+
+const (
+	// AspectProgressive is ている (読んでいる).
+	AspectProgressive Aspect = iota
+	// AspectResultative is てある (書いてある).
+	AspectResultative
+	// AspectPreparatory is ておく (読んでおく).
+	AspectPreparatory
+	// AspectCompletive is てしまう (読んでしまう).
+	AspectCompletive
+	// AspectAway is ていく (増えていく).
+	AspectAway
+	// AspectToward is てくる (増えてくる).
+	AspectToward
+	// AspectTrial is てみる (読んでみる).
+	AspectTrial
+)
+
+// aspectVerbs maps the base form of the auxiliary verb to its aspect.
+var aspectVerbs = map[string]Aspect{
+	"いる":  AspectProgressive,
+	"ある":  AspectResultative,
+	"おく":  AspectPreparatory,
+	"しまう": AspectCompletive,
+	"いく":  AspectAway,
+	"くる":  AspectToward,
+	"みる":  AspectTrial,
+}
+
+// String returns a human readable name of the aspect.
+func (a Aspect) String() string {
+	switch a {
+	case AspectProgressive:
+		return "progressive"
+	case AspectResultative:
+		return "resultative"
+	case AspectPreparatory:
+		return "preparatory"
+	case AspectCompletive:
+		return "completive"
+	case AspectAway:
+		return "away"
+	case AspectToward:
+		return "toward"
+	case AspectTrial:
+		return "trial"
+	default:
+		return "unknown"
+	}
+}
+
+// Predicate describes the main predicate of a sentence.
+type Predicate struct {
+	Head       MorphemeInfo   // 主辞（動詞・形容詞・名詞・形容動詞語幹）
+	Class      PredicateClass // 品詞の種類
+	Tense      Tense          // 時制
+	Polarity   Polarity       // 肯定・否定
+	Politeness Register       // 敬体・常体
+	Modality   Modality       // モダリティ
+	Aspect     []Aspect       // アスペクト（現れる順）
+
+	Conjecture   Conjecture // 推量の表現（ModalityConjecture のとき）
+	Copula       CopulaForm // コピュラの形（だ・である・じゃ）
+	Desiderative bool       // 願望の「たい」を伴う（読みたい）
+	Explanatory  bool       // 説明の「の」を伴う（読むのだ）
+
+	// Start and End are the indices of the first morpheme of the predicate and
+	// of the morpheme just after it, and HeadIndex is the index of the head.
+	// Final particles and punctuation are not included.
+	Start     int
+	End       int
+	HeadIndex int
+}
+
+// AnalyzePredicate analyses the sentence and describes its main predicate.
+// It returns nil when the sentence has no main predicate, e.g. when it ends
+// with a noun (体言止め).
+func (c *Converter) AnalyzePredicate(sentence string) (*Predicate, error) {
+	morphemes, err := c.AnalyzeMorphemes(sentence)
+	if err != nil {
+		return nil, err
+	}
+	return analyzePredicate(morphemes), nil
+}
+
+// analyzePredicate finds the main predicate, which is the last predicate phrase
+// of the sentence, and describes it.
+func analyzePredicate(morphemes []MorphemeInfo) *Predicate {
+	chunks := chunkMorphemes(morphemes)
+	last := len(chunks) - 1
+	for last >= 0 && !chunks[last].Predicate && !(last == len(chunks)-1 && isBareQuestion(chunks[last])) {
+		last--
+	}
+	if last < 0 {
+		return nil
+	}
+
+	// A phrase after the predicate makes it a modifier (美しい花。) unless the
+	// sentence is inverted (行くよ、明日は。)
+	for _, b := range chunks[last+1:] {
+		if b.Head >= 0 && !isInverted(morphemes) {
+			return nil
+		}
+	}
+
+	b := chunks[last]
+	p := &Predicate{Start: b.Start, End: b.Start + len(b.Morphemes)}
+	head := b.Start + b.Head
+
+	// ある and ない after an adjective or a copula, and しれない after かも,
+	// belong to the predicate before them: 美しく|ありません, 学生では|ありません,
+	// 食べたく|ありません, 行くかも|しれない
+	if last > 0 && b.Head == firstContent(b) && isSupportedPhrase(chunks[last-1], b.Morphemes[b.Head]) {
+		prev := chunks[last-1]
+		p.Start = prev.Start
+		head = prev.Start + prev.Head
+	}
+
+	for p.Start < head && morphemes[p.Start].PartOfSpeech == "記号" {
+		p.Start++
+	}
+	for p.End > head+1 && (morphemes[p.End-1].PartOfSpeech == "記号" || isSentenceFinalParticle(morphemes[p.End-1])) {
+		p.End--
+	}
+	p.Head = morphemes[head]
+	p.HeadIndex = head
+	p.Class = predicateClass(morphemes[head])
+
+	describePredicate(p, morphemes[head:p.End])
+	p.Modality = predicateModality(morphemes, p.End, p.Modality)
+	return p
+}
+
+// isBareQuestion reports whether the phrase is a casual question that ends with
+// a noun or na-adjective without a copula, e.g. 元気？ or 学生か。
+func isBareQuestion(b Bunsetsu) bool {
+	if b.Head < 0 || b.Morphemes[b.Head].PartOfSpeech != "名詞" {
+		return false
+	}
+	question := false
+	for _, m := range b.Morphemes[b.Head+1:] {
+		switch {
+		case isSentenceFinalParticle(m) && m.Surface == "か", m.Surface == "？" || m.Surface == "?":
+			question = true
+		case m.PartOfSpeech != "記号":
+			return false
+		}
+	}
+	return question
+}
+
+// firstContent returns the position of the first content morpheme in the phrase.
+func firstContent(b Bunsetsu) int {
+	for i, m := range b.Morphemes {
+		if isContentMorpheme(m) {
+			return i
+		}
+	}
+	return -1
+}
+
+// isSupportedPhrase reports whether the phrase b forms one predicate with the
+// support verb that follows it: an adjective or a verb with たい in the
+// continuative form (美しく, 食べたく) or a noun followed by a copula で or じゃ
+// (学生では, 静かでも) before ある or ない, or a predicate ending with かも
+// before しれる.
+func isSupportedPhrase(b Bunsetsu, support MorphemeInfo) bool {
+	head, ok := b.HeadMorpheme()
+	if !ok {
+		return false
+	}
+	functions := b.FunctionMorphemes()
+
+	switch {
+	case support.PartOfSpeech == "動詞" && support.BaseForm == "しれる":
+		return len(functions) > 0 && functions[len(functions)-1].Surface == "かも"
+	case support.PartOfSpeech == "動詞" && support.BaseForm == "ある":
+	case support.PartOfSpeech == "形容詞" && support.BaseForm == "ない":
+	default:
+		return false
+	}
+
+	switch head.PartOfSpeech {
+	case "形容詞":
+		return len(functions) == 0 && head.InflectionForm == "連用テ接続"
+	case "動詞":
+		return len(functions) > 0 && functions[len(functions)-1].BaseForm == "たい" &&
+			functions[len(functions)-1].InflectionForm == "連用テ接続"
+	case "名詞":
+		if len(functions) == 0 || (functions[0].Surface != "で" && functions[0].Surface != "じゃ") {
+			return false
+		}
+		for _, m := range functions[1:] {
+			if m.Surface != "は" && m.Surface != "も" {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// predicateClass returns the class of the predicate with the head.
+func predicateClass(head MorphemeInfo) PredicateClass {
+	switch head.PartOfSpeech {
+	case "動詞":
+		return PredicateVerb
+	case "形容詞":
+		return PredicateAdjective
+	}
+	if head.PartOfSpeechDetail1 == "形容動詞語幹" {
+		return PredicateNaAdjective
+	}
+	return PredicateNounCopula
+}
+
+// describePredicate sets the tense, polarity, politeness, aspect, modality and
+// the forms of the copula and the conjecture expressed by the morphemes from
+// the head to the end of the predicate.
+func describePredicate(p *Predicate, morphemes []MorphemeInfo) {
+	p.Politeness = RegisterCasual
+	afterShireru := false // しれない の ない は否定ではない
+	for i, m := range morphemes {
+		if m.PartOfSpeech == "動詞" && m.BaseForm == "しれる" {
+			p.Modality = ModalityConjecture
+			p.Conjecture = ConjectureKamoshirenai
+			afterShireru = true
+			continue
+		}
+		if i > 0 && m.PartOfSpeech == "名詞" && m.PartOfSpeechDetail1 == "非自立" && m.Surface == "の" {
+			// 読むのだ, 読むのです
+			p.Explanatory = true
+			continue
+		}
+		if m.PartOfSpeech == "助詞" && m.Surface == "じゃ" {
+			p.Copula = CopulaContracted
+			continue
+		}
+
+		if i > 0 && m.PartOfSpeech == "動詞" && isTeParticle(morphemes[i-1]) {
+			if aspect, ok := aspectVerbs[m.BaseForm]; ok {
+				p.Aspect = append(p.Aspect, aspect)
+			}
+		}
+		if m.PartOfSpeech == "動詞" && (m.BaseForm == "くださる" || m.BaseForm == "下さる" || m.BaseForm == "なさる") {
+			p.Modality = ModalityRequest
+		}
+		if m.PartOfSpeech == "動詞" && strings.HasPrefix(m.InflectionForm, "命令") {
+			p.Modality = ModalityRequest
+		}
+		if isPoliteImperative(m) {
+			p.Politeness = RegisterPolite
+		}
+
+		if i > 0 && m.PartOfSpeech == "形容詞" && m.BaseForm == "ない" && !afterShireru {
+			// ない after 美しく or 静かでは is tokenized as an adjective
+			p.Polarity = PolarityNegative
+			continue
+		}
+
+		if m.PartOfSpeech == "名詞" && m.PartOfSpeechDetail2 == "助動詞語幹" {
+			// ようだ, そうだ
+			p.Modality = ModalityConjecture
+			p.Conjecture = ConjectureOther
+			continue
+		}
+		if m.PartOfSpeech != "助動詞" {
+			continue
+		}
+
+		// The past auxiliary is た or, after 読ん and 泳い, だ
+		if m.InflectionType == "特殊・タ" {
+			p.Tense = TensePast
+			continue
+		}
+
+		switch m.BaseForm {
+		case "ない", "ぬ", "ん":
+			if !afterShireru {
+				p.Polarity = PolarityNegative
+			}
+		case "ます", "です":
+			p.Politeness = RegisterPolite
+		case "らしい":
+			p.Modality = ModalityConjecture
+			p.Conjecture = ConjectureOther
+		case "たい":
+			p.Desiderative = true
+		case "ある":
+			// である, であります
+			if i > 0 && morphemes[i-1].BaseForm == "だ" {
+				p.Copula = CopulaWritten
+			}
+		case "う", "よう":
+			// でしょう and だろう are conjectures; 読もう and 読みましょう are volitional
+			if prev := morphemes[i-1]; prev.BaseForm == "です" || prev.BaseForm == "だ" {
+				p.Modality = ModalityConjecture
+			} else {
+				p.Modality = ModalityVolitional
+			}
+		}
+	}
+}
+
+// predicateModality returns the modality of the predicate ending at end,
+// taking the final particles and punctuation after it into account.
+func predicateModality(morphemes []MorphemeInfo, end int, modality Modality) Modality {
+	for _, m := range morphemes[end:] {
+		switch {
+		case isSentenceFinalParticle(m) && m.Surface == "か":
+			return ModalityQuestion
+		case m.Surface == "？" || m.Surface == "?":
+			return ModalityQuestion
+		}
+	}
+
+	return modality
+}
+
+// isTeParticle reports whether the morpheme is the conjunctive particle て or で.
+func isTeParticle(m MorphemeInfo) bool {
+	return m.PartOfSpeech == "助詞" && m.PartOfSpeechDetail1 == "接続助詞" && (m.Surface == "て" || m.Surface == "で")
+}
@@ -0,0 +1,94 @@
+package kjconv
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAnalyzePredicate(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		input      string
+		head       string
+		class      PredicateClass
+		tense      Tense
+		polarity   Polarity
+		politeness Register
+		modality   Modality
+		aspect     string
+		span       string
+	}{
+		{"本を読む。", "読む", PredicateVerb, TenseNonPast, PolarityAffirmative, RegisterCasual, ModalityNone, "", "読む"},
+		{"本を読みました。", "読み", PredicateVerb, TensePast, PolarityAffirmative, RegisterPolite, ModalityNone, "", "読みました"},
+		{"本を読んだ。", "読ん", PredicateVerb, TensePast, PolarityAffirmative, RegisterCasual, ModalityNone, "", "読んだ"},
+		{"本を読みません。", "読み", PredicateVerb, TenseNonPast, PolarityNegative, RegisterPolite, ModalityNone, "", "読みません"},
+		{"本を読んでいなかった。", "読ん", PredicateVerb, TensePast, PolarityNegative, RegisterCasual, ModalityNone, "progressive", "読んでいなかった"},
+		{"読んでしまった。", "読ん", PredicateVerb, TensePast, PolarityAffirmative, RegisterCasual, ModalityNone, "completive", "読んでしまった"},
+		{"美しい。", "美しい", PredicateAdjective, TenseNonPast, PolarityAffirmative, RegisterCasual, ModalityNone, "", "美しい"},
+		{"美しくありません。", "美しく", PredicateAdjective, TenseNonPast, PolarityNegative, RegisterPolite, ModalityNone, "", "美しくありません"},
+		{"美しかった。", "美しかっ", PredicateAdjective, TensePast, PolarityAffirmative, RegisterCasual, ModalityNone, "", "美しかった"},
+		{"静かではありません。", "静か", PredicateNaAdjective, TenseNonPast, PolarityNegative, RegisterPolite, ModalityNone, "", "静かではありません"},
+		{"静かではなかった。", "静か", PredicateNaAdjective, TensePast, PolarityNegative, RegisterCasual, ModalityNone, "", "静かではなかった"},
+		{"彼は学生だった。", "学生", PredicateNounCopula, TensePast, PolarityAffirmative, RegisterCasual, ModalityNone, "", "学生だった"},
+		{"学生じゃない。", "学生", PredicateNounCopula, TenseNonPast, PolarityNegative, RegisterCasual, ModalityNone, "", "学生じゃない"},
+		{"読みましょう。", "読み", PredicateVerb, TenseNonPast, PolarityAffirmative, RegisterPolite, ModalityVolitional, "", "読みましょう"},
+		{"明日は雨だろう。", "雨", PredicateNounCopula, TenseNonPast, PolarityAffirmative, RegisterCasual, ModalityConjecture, "", "雨だろう"},
+		{"行くかもしれない。", "行く", PredicateVerb, TenseNonPast, PolarityAffirmative, RegisterCasual, ModalityConjecture, "", "行くかもしれない"},
+		{"行くでしょうか。", "行く", PredicateVerb, TenseNonPast, PolarityAffirmative, RegisterPolite, ModalityQuestion, "", "行くでしょう"},
+		{"元気？", "元気", PredicateNaAdjective, TenseNonPast, PolarityAffirmative, RegisterCasual, ModalityQuestion, "", "元気"},
+		{"読んでください。", "読ん", PredicateVerb, TenseNonPast, PolarityAffirmative, RegisterPolite, ModalityRequest, "", "読んでください"},
+		{"行くよ、明日は。", "行く", PredicateVerb, TenseNonPast, PolarityAffirmative, RegisterCasual, ModalityNone, "", "行く"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			morphemes, err := converter.AnalyzeMorphemes(tt.input)
+			if err != nil {
+				t.Fatalf("AnalyzeMorphemes() failed: %v", err)
+			}
+			p := analyzePredicate(morphemes)
+			if p == nil {
+				t.Fatalf("analyzePredicate(%q) = nil", tt.input)
+			}
+
+			var aspect []string
+			for _, a := range p.Aspect {
+				aspect = append(aspect, a.String())
+			}
+			var span strings.Builder
+			for _, m := range morphemes[p.Start:p.End] {
+				span.WriteString(m.Surface)
+			}
+
+			got := fmt.Sprintf("%s %v %v %v %v %v [%s] %s", p.Head.Surface, p.Class, p.Tense, p.Polarity,
+				p.Politeness, p.Modality, strings.Join(aspect, ","), span.String())
+			expected := fmt.Sprintf("%s %v %v %v %v %v [%s] %s", tt.head, tt.class, tt.tense, tt.polarity,
+				tt.politeness, tt.modality, tt.aspect, tt.span)
+			if got != expected {
+				t.Errorf("analyzePredicate(%q) = %s, expected %s", tt.input, got, expected)
+			}
+		})
+	}
+}
+
+func TestAnalyzePredicate_NoPredicate(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	for _, input := range []string{"美しい花。", "第1章", "。"} {
+		p, err := converter.AnalyzePredicate(input)
+		if err != nil {
+			t.Fatalf("AnalyzePredicate() failed: %v", err)
+		}
+		if p != nil {
+			t.Errorf("AnalyzePredicate(%q) = %+v, expected nil", input, p)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package kjconv
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Built-in detectors for spans that must never be tokenized or rewritten.
+var (
+	// URLs with a scheme or starting with www. Trailing punctuation such as the
+	// period in "https://example.com." is not part of the URL, and parentheses
+	// are part of it only when they are balanced, as in .../Go_(language).
+	urlPattern = regexp.MustCompile(`(?:(?:https?|ftp)://|www\.)` +
+		`(?:[A-Za-z0-9\-._~:/?#\[\]@!$&'*+,;=%]|\([A-Za-z0-9\-._~:/?#\[\]@!$&'*+,;=%]*\))*` +
+		`(?:[A-Za-z0-9\-_~/#\[\]@$&*+=%]|\([A-Za-z0-9\-._~:/?#\[\]@!$&'*+,;=%]*\))`)
+	// E-mail addresses.
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`)
+	// Inline code in backticks, e.g. `config_dir/だ.txt`.
+	codePattern = regexp.MustCompile("`[^`\n]+`")
+	// File paths such as /usr/bin, ./cmd/main.go, ~/.config, C:\Windows and
+	// config_dir/だ.txt. Directories must be ASCII; the file name may contain
+	// other characters when it has an extension.
+	pathPattern = regexp.MustCompile(`(?:(?:~|\.{1,2})?/|[A-Za-z]:\\)?(?:[\w.\-]+[/\\])+(?:[^\s/\\、。，．「」『』（）()]*\.[A-Za-z0-9]+|[\w.\-]*)`)
+)
+
+// DefaultProtectedPatterns returns the built-in patterns of protected spans used
+// by NewConverter: URLs, e-mail addresses, inline code in backticks and file paths.
+func DefaultProtectedPatterns() []*regexp.Regexp {
+	return []*regexp.Regexp{urlPattern, emailPattern, codePattern, pathPattern}
+}
+
+// SetProtectedPatterns replaces the patterns of protected spans. Text matching
+// any of the patterns is kept as-is by Convert. Pass nil to disable protection.
+func (c *Converter) SetProtectedPatterns(patterns []*regexp.Regexp) {
+	c.protectedPatterns = patterns
+}
+
+// AddProtectedPattern compiles expr and adds it to the patterns of protected spans,
+// e.g. `[A-Z]+-\d+` for issue keys.
+func (c *Converter) AddProtectedPattern(expr string) error {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("invalid protected pattern %q: %w", expr, err)
+	}
+	c.protectedPatterns = append(c.protectedPatterns, re)
+	return nil
+}
+
+// ProcessTextWithProtection processes text while preserving the spans that match
+// any of the patterns. Each span is replaced by a placeholder character before
+// text is passed to processor, and restored in the processed text afterwards.
+// The tokenizer treats a placeholder as a single noun, so the sentence around it
+// is analysed as if the span were a name.
+func ProcessTextWithProtection(text string, patterns []*regexp.Regexp, processor func(string) (string, error)) (string, error) {
+	masked, m := protectSpans(text, patterns)
+	processed, err := processor(masked)
+	if err != nil {
+		return "", err
+	}
+	return m.restore(processed), nil
+}
+
+// Placeholders are taken from the Private Use Area of the BMP, which the
+// tokenizer does not know and which does not occur in ordinary text. Protected
+// spans and markup use separate ranges: a protected span is analysed as a noun,
+// while markup stays a symbol.
+const (
+	placeholderFirst       rune = '\ue000'
+	markupPlaceholderFirst rune = '\uf000'
+	placeholderLast        rune = '\uf8ff'
+)
+
+// isPlaceholder reports whether r may be a placeholder for a protected span or markup.
+func isPlaceholder(r rune) bool {
+	return placeholderFirst <= r && r <= placeholderLast
+}
+
+// isPlaceholderText reports whether s consists only of placeholders for protected spans.
+func isPlaceholderText(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < placeholderFirst || markupPlaceholderFirst <= r {
+			return false
+		}
+	}
+	return true
+}
+
+// findProtectedSpans returns the non-overlapping spans of text matching any of the
+// patterns, ordered by position. Of overlapping matches the one starting first,
+// or the longer one when they start together, is kept.
+func findProtectedSpans(text string, patterns []*regexp.Regexp) []Span {
+	var matches []Span
+	for _, re := range patterns {
+		for _, loc := range re.FindAllStringIndex(text, -1) {
+			if loc[0] < loc[1] {
+				matches = append(matches, Span{Kind: SpanProtected, Text: text[loc[0]:loc[1]], Offset: loc[0]})
+			}
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Offset != matches[j].Offset {
+			return matches[i].Offset < matches[j].Offset
+		}
+		return len(matches[i].Text) > len(matches[j].Text)
+	})
+
+	var spans []Span
+	for _, s := range matches {
+		if len(spans) > 0 && s.Offset < spans[len(spans)-1].end() {
+			continue
+		}
+		spans = append(spans, s)
+	}
+	return spans
+}
+
+// spanMask maps the placeholders in a masked text back to the spans they stand for.
+type spanMask struct {
+	spans      map[rune]Span
+	used       map[rune]bool // 元のテキストに含まれる私用領域の文字
+	next       rune          // 次に使う保護範囲のプレースホルダ
+	nextMarkup rune          // 次に使うマークアップのプレースホルダ
+}
+
+// newSpanMask creates a mask for text. Private use characters that already occur
+// in text are never used as placeholders.
+func newSpanMask(text string) *spanMask {
+	m := &spanMask{
+		spans:      make(map[rune]Span),
+		used:       make(map[rune]bool),
+		next:       placeholderFirst,
+		nextMarkup: markupPlaceholderFirst,
+	}
+	for _, r := range text {
+		if isPlaceholder(r) {
+			m.used[r] = true
+		}
+	}
+	return m
+}
+
+// add assigns a placeholder to the span. It reports false when all placeholders
+// of the range are in use, in which case the span is left unmasked.
+func (m *spanMask) add(span Span) (rune, bool) {
+	next, last := &m.next, markupPlaceholderFirst-1
+	if span.Kind == SpanMarkup {
+		next, last = &m.nextMarkup, placeholderLast
+	}
+
+	r := *next
+	for r <= last && m.used[r] {
+		r++
+	}
+	if r > last {
+		return 0, false
+	}
+	*next = r + 1
+	m.spans[r] = span
+	return r, true
+}
+
+// protectSpans replaces every span of text matching the patterns with a
+// placeholder character. Spans beyond the number of available placeholders
+// are left unprotected.
+func protectSpans(text string, patterns []*regexp.Regexp) (string, *spanMask) {
+	m := newSpanMask(text)
+	spans := findProtectedSpans(text, patterns)
+	if len(spans) == 0 {
+		return text, m
+	}
+
+	var b strings.Builder
+	start := 0
+	for _, s := range spans {
+		r, ok := m.add(s)
+		if !ok {
+			break
+		}
+		b.WriteString(text[start:s.Offset])
+		b.WriteRune(r)
+		start = s.end()
+	}
+	b.WriteString(text[start:])
+	return b.String(), m
+}
+
+// restore replaces the placeholders in s with the spans they stand for.
+func (m *spanMask) restore(s string) string {
+	if len(m.spans) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if span, ok := m.spans[r]; ok {
+			b.WriteString(span.Text)
+		} else {
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
+
+// restoreSentence restores the spans in the texts of a sentence result. The
+// edits of the result are moved from the masked sentence to the restored one.
+func (m *spanMask) restoreSentence(sr *SentenceResult) {
+	m.restoreEdits(sr.Original, sr.Edits)
+	sr.Original = m.restore(sr.Original)
+	sr.Converted = m.restore(sr.Converted)
+	for i := range sr.Warnings {
+		sr.Warnings[i].Text = m.restore(sr.Warnings[i].Text)
+	}
+}
+
+// restoreEdits restores the spans in the edits of the masked text and moves
+// them to the positions in the restored text. The edits must be in increasing
+// order of position.
+func (m *spanMask) restoreEdits(masked string, edits []TextEdit) {
+	if len(m.spans) == 0 {
+		return
+	}
+
+	shift := 0 // 復元による位置のずれ
+	i := 0     // ずれを数え終えた位置
+	restored := func(offset int) int {
+		for i < offset {
+			r, size := utf8.DecodeRuneInString(masked[i:])
+			if span, ok := m.spans[r]; ok {
+				shift += len(span.Text) - size
+			}
+			i += size
+		}
+		return offset + shift
+	}
+	for k := range edits {
+		edits[k].Start = restored(edits[k].Start)
+		edits[k].End = restored(edits[k].End)
+		edits[k].NewText = m.restore(edits[k].NewText)
+	}
+}
@@ -0,0 +1,145 @@
+package kjconv
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestConvert_ProtectedSpans(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		mode     ConversionMode
+		expected string
+	}{
+		{
+			name:     "inline code",
+			input:    "詳細は`config_dir/だ.txt`を見る。",
+			mode:     CasualToPolite,
+			expected: "詳細は`config_dir/だ.txt`を見ます。",
+		},
+		{
+			name:     "inline code polite",
+			input:    "コードは`です`です。",
+			mode:     PoliteToCasual,
+			expected: "コードは`です`だ。",
+		},
+		{
+			name:     "path",
+			input:    "設定は config_dir/だ.txt だ。",
+			mode:     CasualToPolite,
+			expected: "設定は config_dir/だ.txt です。",
+		},
+		{
+			name:     "URL with terminators",
+			input:    "https://example.com/search?q=1. を参照する。次に進む。",
+			mode:     CasualToPolite,
+			expected: "https://example.com/search?q=1. を参照します。次に進みます。",
+		},
+		{
+			name:     "email",
+			input:    "連絡先はfoo.bar@example.comだ。",
+			mode:     CasualToPolite,
+			expected: "連絡先はfoo.bar@example.comです。",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := converter.ConvertDetailed(tt.input, tt.mode)
+			if err != nil {
+				t.Fatalf("ConvertDetailed() failed: %v", err)
+			}
+			if result.Text != tt.expected {
+				t.Errorf("ConvertDetailed().Text = %q, expected %q", result.Text, tt.expected)
+			}
+			for _, sr := range result.Sentences {
+				if strings.ContainsFunc(sr.Original+sr.Converted, isPlaceholder) {
+					t.Errorf("sentence %+v contains a placeholder", sr)
+				}
+			}
+		})
+	}
+}
+
+func TestAddProtectedPattern(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	input := "この件はだ-123で扱う。"
+	if err := converter.AddProtectedPattern(`だ-\d+`); err != nil {
+		t.Fatalf("AddProtectedPattern() failed: %v", err)
+	}
+	result, err := converter.Convert(input, CasualToPolite)
+	if err != nil {
+		t.Fatalf("Convert() failed: %v", err)
+	}
+	if expected := "この件はだ-123で扱います。"; result != expected {
+		t.Errorf("Convert() = %q, expected %q", result, expected)
+	}
+
+	if err := converter.AddProtectedPattern(`(`); err == nil {
+		t.Error("AddProtectedPattern() with an invalid pattern succeeded")
+	}
+}
+
+func TestFindProtectedSpans(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{name: "URL", input: "https://example.com/a_(b)を見る。", expected: []string{"https://example.com/a_(b)"}},
+		{name: "URL trailing period", input: "See https://example.com.", expected: []string{"https://example.com"}},
+		{name: "www", input: "www.example.comだ。", expected: []string{"www.example.com"}},
+		{name: "email", input: "宛先はa+b@mail.example.jpだ。", expected: []string{"a+b@mail.example.jp"}},
+		{name: "code", input: "`a`と`b`を使う。", expected: []string{"`a`", "`b`"}},
+		{name: "absolute path", input: "/usr/local/binにある。", expected: []string{"/usr/local/bin"}},
+		{name: "relative path", input: "./cmd/main.goを開く。", expected: []string{"./cmd/main.go"}},
+		{name: "home path", input: "~/.config/kjconvに置く。", expected: []string{"~/.config/kjconv"}},
+		{name: "windows path", input: `C:\Users\nameを開く。`, expected: []string{`C:\Users\name`}},
+		{name: "non-ASCII file name", input: "config_dir/だ.txtを開く。", expected: []string{"config_dir/だ.txt"}},
+		{name: "URL wins over path", input: "http://a.example/b/cを見る。", expected: []string{"http://a.example/b/c"}},
+		{name: "none", input: "今日は晴れだ。", expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, s := range findProtectedSpans(tt.input, DefaultProtectedPatterns()) {
+				got = append(got, s.Text)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("findProtectedSpans(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProcessTextWithProtection(t *testing.T) {
+	input := "\ue000と`code`と`more`"
+	patterns := []*regexp.Regexp{codePattern}
+
+	var masked string
+	result, err := ProcessTextWithProtection(input, patterns, func(s string) (string, error) {
+		masked = s
+		return strings.ReplaceAll(s, "と", "や"), nil
+	})
+	if err != nil {
+		t.Fatalf("ProcessTextWithProtection() failed: %v", err)
+	}
+	if strings.Contains(masked, "`") {
+		t.Errorf("processor received %q, expected the code spans to be masked", masked)
+	}
+	if expected := "\ue000や`code`や`more`"; result != expected {
+		t.Errorf("ProcessTextWithProtection() = %q, expected %q", result, expected)
+	}
+}
@@ -0,0 +1,119 @@
+package kjconv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QuotePolicy selects how quotations (「」 and 『』) are handled by Convert.
+type QuotePolicy int
+
+const (
+	// QuoteNarration keeps the quotation as-is and converts only the narration
+	// around it, e.g. 「行くよ」と言った。 → 「行くよ」と言いました。 This is the default.
+	QuoteNarration QuotePolicy = iota
+	// QuoteConvert converts the sentences inside the quotation as well. It suits
+	// documents such as FAQ pages that put whole answers in brackets.
+	QuoteConvert
+	// QuoteSkip leaves every sentence containing the quotation unchanged.
+	QuoteSkip
+)
+
+// String returns a human readable name of the policy.
+func (p QuotePolicy) String() string {
+	switch p {
+	case QuoteNarration:
+		return "narration"
+	case QuoteConvert:
+		return "convert"
+	case QuoteSkip:
+		return "skip"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseQuotePolicy returns the policy named s ("narration", "convert" or "skip").
+func ParseQuotePolicy(s string) (QuotePolicy, error) {
+	for _, p := range []QuotePolicy{QuoteNarration, QuoteConvert, QuoteSkip} {
+		if s == p.String() {
+			return p, nil
+		}
+	}
+	return QuoteNarration, fmt.Errorf("unknown quote policy: %q", s)
+}
+
+// SetQuotePolicy sets the policy for quotations opened by the given brackets,
+// e.g. SetQuotePolicy(QuoteConvert, '「'). Without brackets the policy applies
+// to all quotations. Brackets other than 「 and 『 are rejected.
+func (c *Converter) SetQuotePolicy(policy QuotePolicy, brackets ...rune) error {
+	if len(brackets) == 0 {
+		for open := range quoteBrackets {
+			brackets = append(brackets, open)
+		}
+	}
+	for _, open := range brackets {
+		if !quoteBrackets[open] {
+			return fmt.Errorf("not a quotation bracket: %q", open)
+		}
+	}
+
+	if c.quotePolicies == nil {
+		c.quotePolicies = make(map[rune]QuotePolicy)
+	}
+	for _, open := range brackets {
+		c.quotePolicies[open] = policy
+	}
+	return nil
+}
+
+// quotePolicy returns the policy for quotations opened by the bracket.
+func (c *Converter) quotePolicy(open rune) QuotePolicy {
+	return c.quotePolicies[open]
+}
+
+// convertQuotes applies the quote policies to the outermost quotations in the
+// sentence. It reports false when the sentence must be left unchanged because
+// of QuoteSkip; otherwise it returns the sentence with the content of the
+// quotations under QuoteConvert converted, and the changes as edits against
+// the sentence.
+func (c *Converter) convertQuotes(sr *SentenceResult, sentence string, mode ConversionMode) (string, []TextEdit, bool, error) {
+	quotes := outermostSpans(scanBrackets(sentence), bracketSpan.isQuote)
+	for _, quote := range quotes {
+		if c.quotePolicy(quote.Open) == QuoteSkip {
+			return sentence, nil, false, nil
+		}
+	}
+
+	var b strings.Builder
+	var edits []TextEdit
+	start := 0
+	for _, quote := range quotes {
+		if c.quotePolicy(quote.Open) != QuoteConvert {
+			continue
+		}
+
+		// The brackets are one character wide on both sides
+		openEnd := quote.Start + len(string(quote.Open))
+		closeStart := quote.End - len(string(bracketPairs[quote.Open]))
+		converted, sentences, err := c.convertText(sentence[openEnd:closeStart], mode)
+		if err != nil {
+			return sentence, nil, true, err
+		}
+		fired := firedRules(sr.Rules)
+		for _, inner := range sentences {
+			sr.Warnings = append(sr.Warnings, inner.Warnings...)
+			edits = append(edits, shiftEdits(inner.Edits, openEnd)...)
+			for _, id := range inner.Rules {
+				fired.add(id)
+			}
+		}
+		sr.Rules = fired
+
+		b.WriteString(sentence[start:openEnd])
+		b.WriteString(converted)
+		start = closeStart
+	}
+	b.WriteString(sentence[start:])
+	return b.String(), edits, true, nil
+}
@@ -0,0 +1,108 @@
+package kjconv
+
+import (
+	"testing"
+)
+
+func TestConvert_QuotePolicy(t *testing.T) {
+	input := "「晴れだ。雨だ。」と言った。『罪と罰』を読む。「『雪国』だ」と言った。"
+
+	tests := []struct {
+		name     string
+		policies map[rune]QuotePolicy
+		expected string
+	}{
+		{
+			name:     "default",
+			expected: "「晴れだ。雨だ。」と言いました。『罪と罰』を読みます。「『雪国』だ」と言いました。",
+		},
+		{
+			name:     "convert",
+			policies: map[rune]QuotePolicy{'「': QuoteConvert, '『': QuoteConvert},
+			expected: "「晴れです。雨です。」と言いました。『罪と罰』を読みます。「『雪国』です」と言いました。",
+		},
+		{
+			name:     "skip",
+			policies: map[rune]QuotePolicy{'「': QuoteSkip, '『': QuoteSkip},
+			expected: input,
+		},
+		{
+			name:     "per bracket",
+			policies: map[rune]QuotePolicy{'「': QuoteConvert, '『': QuoteSkip},
+			expected: "「晴れです。雨です。」と言いました。『罪と罰』を読む。「『雪国』だ」と言いました。",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter, err := NewConverter()
+			if err != nil {
+				t.Fatalf("NewConverter() failed: %v", err)
+			}
+			for open, policy := range tt.policies {
+				if err := converter.SetQuotePolicy(policy, open); err != nil {
+					t.Fatalf("SetQuotePolicy() failed: %v", err)
+				}
+			}
+
+			result, err := converter.Convert(input, CasualToPolite)
+			if err != nil {
+				t.Fatalf("Convert() failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Convert() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertDetailed_SkipQuoted(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	result, err := converter.ConvertDetailed("「晴れだ。」", CasualToPolite)
+	if err != nil {
+		t.Fatalf("ConvertDetailed() failed: %v", err)
+	}
+	if reason := result.Sentences[0].SkipReason; reason != SkipQuoted {
+		t.Errorf("SkipReason = %v, expected %v", reason, SkipQuoted)
+	}
+
+	if err := converter.SetQuotePolicy(QuoteConvert); err != nil {
+		t.Fatalf("SetQuotePolicy() failed: %v", err)
+	}
+	result, err = converter.ConvertDetailed("「晴れだ。」", CasualToPolite)
+	if err != nil {
+		t.Fatalf("ConvertDetailed() failed: %v", err)
+	}
+	if result.Text != "「晴れです。」" {
+		t.Errorf("ConvertDetailed().Text = %q, expected %q", result.Text, "「晴れです。」")
+	}
+	if reason := result.Sentences[0].SkipReason; reason != SkipNone {
+		t.Errorf("SkipReason = %v, expected %v", reason, SkipNone)
+	}
+}
+
+func TestSetQuotePolicy_InvalidBracket(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+	if err := converter.SetQuotePolicy(QuoteConvert, '（'); err == nil {
+		t.Error("SetQuotePolicy() with （ succeeded, expected an error")
+	}
+}
+
+func TestParseQuotePolicy(t *testing.T) {
+	for _, p := range []QuotePolicy{QuoteNarration, QuoteConvert, QuoteSkip} {
+		got, err := ParseQuotePolicy(p.String())
+		if err != nil || got != p {
+			t.Errorf("ParseQuotePolicy(%q) = %v, %v, expected %v", p.String(), got, err, p)
+		}
+	}
+	if _, err := ParseQuotePolicy("always"); err == nil {
+		t.Error("ParseQuotePolicy(\"always\") succeeded, expected an error")
+	}
+}
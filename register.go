@@ -0,0 +1,106 @@
+package kjconv
+
+import "strings"
+
+// Register represents the speech style (文体) of a sentence.
+type Register int
+
+const (
+	// RegisterUnknown is used when the style cannot be determined, e.g. 体言止め.
+	RegisterUnknown Register = iota
+	// RegisterCasual is the plain style (常体).
+	RegisterCasual
+	// RegisterPolite is the polite style (敬体).
+	RegisterPolite
+)
+
+// String returns a human readable name of the register.
+func (r Register) String() string {
+	switch r {
+	case RegisterCasual:
+		return "casual"
+	case RegisterPolite:
+		return "polite"
+	default:
+		return "unknown"
+	}
+}
+
+// targetRegister returns the register that sentences are converted into.
+func (m ConversionMode) targetRegister() Register {
+	switch m {
+	case CasualToPolite:
+		return RegisterPolite
+	case PoliteToCasual:
+		return RegisterCasual
+	default:
+		return RegisterUnknown
+	}
+}
+
+// detectRegister determines the register of a sentence from its final predicate.
+// Trailing punctuation and sentence-final particles (よ, ね, か...) are skipped, and
+// the chain of auxiliary verbs before them decides the style: any ます/です makes
+// the sentence polite, otherwise a verb, adjective or auxiliary makes it casual.
+// A request with ください, なさい or いらっしゃい is polite, and a question
+// ending with a noun without a copula (元気？, 学生か) is casual.
+func detectRegister(morphemes []MorphemeInfo) Register {
+	i := len(morphemes) - 1
+	question := false
+	for i >= 0 && (morphemes[i].PartOfSpeech == "記号" || isSentenceFinalParticle(morphemes[i])) {
+		switch morphemes[i].Surface {
+		case "か", "？", "?":
+			question = true
+		}
+		i--
+	}
+	if i < 0 {
+		return RegisterUnknown
+	}
+
+	switch morphemes[i].PartOfSpeech {
+	case "助動詞":
+		for ; i >= 0 && morphemes[i].PartOfSpeech == "助動詞"; i-- {
+			if isPoliteAuxiliary(morphemes[i]) {
+				return RegisterPolite
+			}
+		}
+		return RegisterCasual
+	case "動詞", "形容詞":
+		if isPoliteImperative(morphemes[i]) {
+			return RegisterPolite
+		}
+		return RegisterCasual
+	case "名詞":
+		if question {
+			return RegisterCasual
+		}
+	}
+
+	return RegisterUnknown
+}
+
+// isPoliteImperative reports whether the morpheme is the imperative of an
+// honorific verb, which makes a polite request or command: ください (読んでください),
+// なさい (寝なさい) or いらっしゃい.
+func isPoliteImperative(m MorphemeInfo) bool {
+	if m.PartOfSpeech != "動詞" || !strings.HasPrefix(m.InflectionForm, "命令") {
+		return false
+	}
+	switch m.BaseForm {
+	case "くださる", "下さる", "なさる", "いらっしゃる":
+		return true
+	}
+	return false
+}
+
+// isPoliteAuxiliary reports whether the morpheme is the polite auxiliary ます or です.
+func isPoliteAuxiliary(m MorphemeInfo) bool {
+	return m.PartOfSpeech == "助動詞" && (m.BaseForm == "ます" || m.BaseForm == "です")
+}
+
+// isSentenceFinalParticle reports whether the morpheme is a 終助詞 such as よ or ね.
+func isSentenceFinalParticle(m MorphemeInfo) bool {
+	return m.PartOfSpeech == "助詞" && (m.PartOfSpeechDetail1 == "終助詞" ||
+		m.PartOfSpeechDetail1 == "副助詞／並立助詞／終助詞")
+}
@@ -0,0 +1,107 @@
+package kjconv
+
+import (
+	"testing"
+)
+
+func TestDetectRegister(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected Register
+	}{
+		{
+			name:     "です",
+			input:    "今日は晴れです。",
+			expected: RegisterPolite,
+		},
+		{
+			name:     "ます",
+			input:    "本を読みます。",
+			expected: RegisterPolite,
+		},
+		{
+			name:     "ませんでした",
+			input:    "本を読みませんでした。",
+			expected: RegisterPolite,
+		},
+		{
+			name:     "終助詞付きの敬体",
+			input:    "美しいですね。",
+			expected: RegisterPolite,
+		},
+		{
+			name:     "疑問文の敬体",
+			input:    "元気ですか？",
+			expected: RegisterPolite,
+		},
+		{
+			name:     "てください",
+			input:    "本を読んでください。",
+			expected: RegisterPolite,
+		},
+		{
+			name:     "なさい",
+			input:    "早く寝なさい。",
+			expected: RegisterPolite,
+		},
+		{
+			name:     "コピュラのない疑問文",
+			input:    "元気？",
+			expected: RegisterCasual,
+		},
+		{
+			name:     "命令形",
+			input:    "ここに座れ。",
+			expected: RegisterCasual,
+		},
+		{
+			name:     "だ",
+			input:    "今日は晴れだ。",
+			expected: RegisterCasual,
+		},
+		{
+			name:     "動詞基本形",
+			input:    "本を読む。",
+			expected: RegisterCasual,
+		},
+		{
+			name:     "形容詞",
+			input:    "この花は美しい。",
+			expected: RegisterCasual,
+		},
+		{
+			name:     "文中の敬体は文末で判定する",
+			input:    "行きますが、雨だ。",
+			expected: RegisterCasual,
+		},
+		{
+			name:     "体言止め",
+			input:    "晴れ。",
+			expected: RegisterUnknown,
+		},
+		{
+			name:     "空文字列",
+			input:    "",
+			expected: RegisterUnknown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			morphemes, err := converter.AnalyzeMorphemes(tt.input)
+			if err != nil {
+				t.Fatalf("AnalyzeMorphemes() failed: %v", err)
+			}
+			result := detectRegister(morphemes)
+			if result != tt.expected {
+				t.Errorf("detectRegister(%q) = %v, expected %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
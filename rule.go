@@ -0,0 +1,299 @@
+package kjconv
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// IDs of the built-in conversion rules. Each rule can be disabled with
+// SetRuleEnabled or WithDisabledRules, and removed or reordered in the
+// converter's RuleRegistry.
+const (
+	// RulePredicate regenerates the main predicate in the target register
+	// (読まなかった ⇔ 読みませんでした). When it is disabled or cannot
+	// reproduce the predicate, the rules below are applied instead.
+	RulePredicate = "predicate"
+	// RuleVerb converts verbs (読む ⇔ 読みます).
+	RuleVerb = "verb"
+	// RuleAdjective converts i-adjectives (美しい ⇔ 美しいです).
+	RuleAdjective = "adjective"
+	// RuleCopula converts the copula after nouns and na-adjectives (学生だ ⇔ 学生です).
+	RuleCopula = "copula"
+	// RuleAuxiliary converts auxiliaries and set phrases (だろう ⇔ でしょう).
+	RuleAuxiliary = "auxiliary"
+	// RuleNegative converts negatives (読まない ⇔ 読みません).
+	RuleNegative = "negative"
+	// RuleConjunction converts conjunctions at the start of a sentence (だから ⇔ ですから).
+	RuleConjunction = "conjunction"
+)
+
+// RuleIDs returns the IDs of all built-in conversion rules.
+func RuleIDs() []string {
+	return []string{RulePredicate, RuleVerb, RuleAdjective, RuleCopula, RuleAuxiliary, RuleNegative, RuleConjunction}
+}
+
+// SetRuleEnabled enables or disables the conversion rule with the ID, which is
+// a built-in rule or one added to the converter's RuleRegistry. All rules are
+// enabled by default.
+func (c *Converter) SetRuleEnabled(id string, enabled bool) error {
+	if !slices.Contains(RuleIDs(), id) && !c.rules.has(id) {
+		return fmt.Errorf("unknown rule: %q", id)
+	}
+
+	if c.disabledRules == nil {
+		c.disabledRules = make(map[string]bool)
+	}
+	c.disabledRules[id] = !enabled
+	return nil
+}
+
+// ruleEnabled reports whether the rule with the ID is enabled.
+func (c *Converter) ruleEnabled(id string) bool {
+	return !c.disabledRules[id]
+}
+
+// firedRules collects the IDs of the rules that changed a sentence, in the
+// order they first fired. A nil *firedRules discards them.
+type firedRules []string
+
+// add records that the rule with the ID fired.
+func (f *firedRules) add(id string) {
+	if f != nil && !slices.Contains(*f, id) {
+		*f = append(*f, id)
+	}
+}
+
+// Rule is a conversion rule. It matches a window of the morphemes of a
+// sentence and rewrites it, e.g. はずだ → はずです. Rules are applied in order of
+// priority, each at most once per sentence, to the morphemes rewritten by the
+// rules before it; see RuleRegistry.
+type Rule interface {
+	// ID returns the ID of the rule, which is reported in SentenceResult.Rules
+	// when the rule changes a sentence.
+	ID() string
+	// Direction returns the mode the rule converts in, CasualToPolite or
+	// PoliteToCasual.
+	Direction() ConversionMode
+	// Priority returns the priority of the rule. Rules with a higher priority
+	// are applied first.
+	Priority() int
+	// Match returns the window morphemes[start:end] that the rule rewrites, or
+	// false when the rule does not apply to the morphemes.
+	Match(morphemes []MorphemeInfo) (start, end int, ok bool)
+	// Rewrite returns the morphemes that replace the window returned by Match.
+	// The window may be modified.
+	Rewrite(window []MorphemeInfo) []MorphemeInfo
+}
+
+// RuleRegistry holds the conversion rules of a Converter. It starts with the
+// built-in rules, whose priorities are
+//
+//	RulePredicate 700, RuleVerb 600, RuleAdjective 500, RuleCopula 400,
+//	RuleAuxiliary 300, RuleNegative 200, RuleConjunction 100
+//
+// so that a rule can be placed between them. RuleVerb, RuleAdjective,
+// RuleCopula, RuleAuxiliary and RuleNegative are applied only to sentences
+// whose ending no other rule before them has rewritten, such as those whose
+// predicate RulePredicate could not convert. These fallbacks patch the
+// surfaces of the sentence ending rather than generating it, and are still
+// used for
+//
+//   - conjectures other than だろう and かもしれない (降るらしい, 雨のようだ),
+//   - the contracted explanatory ん (読むんだ),
+//   - requests and commands (読んでください), and
+//   - predicates that cannot be analysed or whose regenerated form differs
+//     from the input.
+type RuleRegistry struct {
+	rules []registeredRule
+}
+
+// registeredRule is a rule in a registry with its current priority.
+type registeredRule struct {
+	rule     Rule
+	priority int
+}
+
+// Add adds a rule. The ID must not be empty, and no other rule of the same
+// direction may have it.
+func (r *RuleRegistry) Add(rule Rule) error {
+	if rule == nil {
+		return errors.New("rule is nil")
+	}
+	id, direction := rule.ID(), rule.Direction()
+	if id == "" {
+		return errors.New("rule has no ID")
+	}
+	if direction != CasualToPolite && direction != PoliteToCasual {
+		return fmt.Errorf("rule %q has an unsupported direction: %d", id, direction)
+	}
+	for _, registered := range r.rules {
+		if registered.rule.ID() == id && registered.rule.Direction() == direction {
+			return fmt.Errorf("duplicate rule: %q", id)
+		}
+	}
+	r.rules = append(r.rules, registeredRule{rule: rule, priority: rule.Priority()})
+	return nil
+}
+
+// Remove removes the rules with the ID in both directions.
+func (r *RuleRegistry) Remove(id string) error {
+	if !r.has(id) {
+		return fmt.Errorf("unknown rule: %q", id)
+	}
+	r.rules = slices.DeleteFunc(r.rules, func(registered registeredRule) bool {
+		return registered.rule.ID() == id
+	})
+	return nil
+}
+
+// SetPriority changes the priority of the rules with the ID in both
+// directions, which moves them before or after other rules.
+func (r *RuleRegistry) SetPriority(id string, priority int) error {
+	if !r.has(id) {
+		return fmt.Errorf("unknown rule: %q", id)
+	}
+	for i := range r.rules {
+		if r.rules[i].rule.ID() == id {
+			r.rules[i].priority = priority
+		}
+	}
+	return nil
+}
+
+// Rules returns the rules of the direction in the order they are applied:
+// by priority, and rules of the same priority in the order they were added.
+func (r *RuleRegistry) Rules(direction ConversionMode) []Rule {
+	var registered []registeredRule
+	for _, rr := range r.rules {
+		if rr.rule.Direction() == direction {
+			registered = append(registered, rr)
+		}
+	}
+	sort.SliceStable(registered, func(i, j int) bool {
+		return registered[i].priority > registered[j].priority
+	})
+
+	rules := make([]Rule, len(registered))
+	for i, rr := range registered {
+		rules[i] = rr.rule
+	}
+	return rules
+}
+
+// has reports whether a rule with the ID is registered.
+func (r *RuleRegistry) has(id string) bool {
+	return slices.ContainsFunc(r.rules, func(registered registeredRule) bool {
+		return registered.rule.ID() == id
+	})
+}
+
+// Rules returns the registry of the converter's conversion rules. Changes to
+// the registry apply to the following conversions.
+func (c *Converter) Rules() *RuleRegistry {
+	return c.rules
+}
+
+// builtinRule is a built-in conversion rule. It rewrites the whole sentence.
+type builtinRule struct {
+	id        string
+	direction ConversionMode
+	priority  int
+	rewrite   func([]MorphemeInfo) []MorphemeInfo
+
+	// fallback is true for the rules that are applied only when no other
+	// rule has rewritten the ending of the sentence
+	fallback bool
+}
+
+func (r *builtinRule) ID() string                { return r.id }
+func (r *builtinRule) Direction() ConversionMode { return r.direction }
+func (r *builtinRule) Priority() int             { return r.priority }
+
+func (r *builtinRule) Match(morphemes []MorphemeInfo) (int, int, bool) {
+	return 0, len(morphemes), len(morphemes) > 0
+}
+
+func (r *builtinRule) Rewrite(window []MorphemeInfo) []MorphemeInfo {
+	return r.rewrite(window)
+}
+
+// newRuleRegistry returns a registry with the built-in rules of the converter.
+func (c *Converter) newRuleRegistry() *RuleRegistry {
+	predicate := func(target Register) func([]MorphemeInfo) []MorphemeInfo {
+		return func(morphemes []MorphemeInfo) []MorphemeInfo {
+			if converted, ok := convertPredicate(morphemes, target, c.style); ok {
+				return converted
+			}
+			return morphemes
+		}
+	}
+
+	r := &RuleRegistry{}
+	for _, b := range []*builtinRule{
+		{id: RulePredicate, direction: CasualToPolite, priority: 700, rewrite: predicate(RegisterPolite)},
+		{id: RuleVerb, direction: CasualToPolite, priority: 600, rewrite: c.convertVerbCasualToPolite, fallback: true},
+		{id: RuleAdjective, direction: CasualToPolite, priority: 500, rewrite: c.convertAdjectiveCasualToPolite, fallback: true},
+		{id: RuleCopula, direction: CasualToPolite, priority: 400, rewrite: c.convertNounCasualToPolite, fallback: true},
+		{id: RuleAuxiliary, direction: CasualToPolite, priority: 300, rewrite: c.convertAuxiliaryCasualToPolite, fallback: true},
+		{id: RuleNegative, direction: CasualToPolite, priority: 200, rewrite: c.handleNegativeCasualToPolite, fallback: true},
+		{id: RuleConjunction, direction: CasualToPolite, priority: 100, rewrite: c.convertConjunctionCasualToPolite},
+
+		{id: RulePredicate, direction: PoliteToCasual, priority: 700, rewrite: predicate(RegisterCasual)},
+		{id: RuleVerb, direction: PoliteToCasual, priority: 600, rewrite: c.convertVerbPoliteToCase, fallback: true},
+		{id: RuleAdjective, direction: PoliteToCasual, priority: 500, rewrite: c.convertAdjectivePoliteToCase, fallback: true},
+		{id: RuleCopula, direction: PoliteToCasual, priority: 400, rewrite: c.convertNounPoliteToCase, fallback: true},
+		{id: RuleAuxiliary, direction: PoliteToCasual, priority: 300, rewrite: c.convertAuxiliaryPoliteToCase, fallback: true},
+		{id: RuleNegative, direction: PoliteToCasual, priority: 200, rewrite: c.handleNegativePoliteToCase, fallback: true},
+		{id: RuleConjunction, direction: PoliteToCasual, priority: 100, rewrite: c.convertConjunctionPoliteToCase},
+	} {
+		r.rules = append(r.rules, registeredRule{rule: b, priority: b.priority})
+	}
+	return r
+}
+
+// applyRules applies the enabled rules of the direction to the morphemes of a
+// sentence and records the rules that changed them in fired.
+func (c *Converter) applyRules(morphemes []MorphemeInfo, direction ConversionMode, fired *firedRules) []MorphemeInfo {
+	ending := false // 文末が書き換えられたか
+	for _, rule := range c.rules.Rules(direction) {
+		if !c.ruleEnabled(rule.ID()) {
+			continue
+		}
+		builtin, isBuiltin := rule.(*builtinRule)
+		fallback := isBuiltin && builtin.fallback
+		if fallback && ending {
+			continue
+		}
+
+		converted, end, changed := c.applyRule(rule, morphemes)
+		if changed {
+			fired.add(rule.ID())
+			ending = ending || (!fallback && end >= contentEnd(morphemes))
+		}
+		morphemes = converted
+	}
+	return morphemes
+}
+
+// applyRule applies a rule to the morphemes. It returns the rewritten
+// morphemes, the end of the window the rule matched, and whether the rule
+// changed the morphemes.
+func (c *Converter) applyRule(rule Rule, morphemes []MorphemeInfo) ([]MorphemeInfo, int, bool) {
+	start, end, ok := rule.Match(morphemes)
+	if !ok {
+		return morphemes, 0, false
+	}
+	if start < 0 || start > end || end > len(morphemes) {
+		c.logger.Warn("rule matched an invalid window", "rule", rule.ID(), "start", start, "end", end, "morphemes", len(morphemes))
+		return morphemes, 0, false
+	}
+
+	window := rule.Rewrite(slices.Clone(morphemes[start:end]))
+	result := make([]MorphemeInfo, 0, len(morphemes)-(end-start)+len(window))
+	result = append(result, morphemes[:start]...)
+	result = append(result, window...)
+	result = append(result, morphemes[end:]...)
+	return result, end, surfaceOf(result) != surfaceOf(morphemes)
+}
@@ -0,0 +1,203 @@
+package kjconv
+
+import (
+	"fmt"
+	"testing"
+)
+
+// endingRule replaces the morpheme before the final punctuation when its
+// surface is from.
+type endingRule struct {
+	id        string
+	direction ConversionMode
+	priority  int
+	from, to  string
+}
+
+func (r endingRule) ID() string                { return r.id }
+func (r endingRule) Direction() ConversionMode { return r.direction }
+func (r endingRule) Priority() int             { return r.priority }
+
+func (r endingRule) Match(morphemes []MorphemeInfo) (int, int, bool) {
+	i := len(morphemes)
+	for i > 0 && morphemes[i-1].PartOfSpeech == "記号" {
+		i--
+	}
+	if i == 0 || morphemes[i-1].Surface != r.from {
+		return 0, 0, false
+	}
+	return i - 1, i, true
+}
+
+func (r endingRule) Rewrite(window []MorphemeInfo) []MorphemeInfo {
+	return []MorphemeInfo{{Surface: r.to, PartOfSpeech: "助動詞", BaseForm: r.to}}
+}
+
+// windowRule matches a fixed window.
+type windowRule struct {
+	endingRule
+	start, end int
+}
+
+func (r windowRule) Match(morphemes []MorphemeInfo) (int, int, bool) {
+	return r.start, r.end, true
+}
+
+func TestRuleRegistry_Add(t *testing.T) {
+	jan := endingRule{id: "jan", direction: CasualToPolite, priority: 800, from: "じゃん", to: "ですね"}
+	converter, err := NewConverter(WithRule(jan))
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	result, err := converter.ConvertDetailed("いいじゃん。", CasualToPolite)
+	if err != nil {
+		t.Fatalf("ConvertDetailed() failed: %v", err)
+	}
+	if result.Text != "いいですね。" {
+		t.Errorf("ConvertDetailed().Text = %q, expected %q", result.Text, "いいですね。")
+	}
+	if rules := result.Sentences[0].Rules; fmt.Sprint(rules) != "[jan]" {
+		t.Errorf("Rules = %q, expected [jan]", rules)
+	}
+
+	// The rule is not applied in the other direction
+	if got, _ := converter.Convert("いいじゃん。", PoliteToCasual); got != "いいじゃん。" {
+		t.Errorf("Convert(PoliteToCasual) = %q, expected it unchanged", got)
+	}
+
+	// Custom rules can be disabled like the built-in ones
+	if err := converter.SetRuleEnabled("jan", false); err != nil {
+		t.Fatalf("SetRuleEnabled() failed: %v", err)
+	}
+	if got, _ := converter.Convert("いいじゃん。", CasualToPolite); got == "いいですね。" {
+		t.Errorf("Convert() with the rule disabled = %q", got)
+	}
+}
+
+func TestRuleRegistry_AddInvalid(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		rule Rule
+	}{
+		{"nil", nil},
+		{"no ID", endingRule{direction: CasualToPolite}},
+		{"unsupported direction", endingRule{id: "x", direction: AutoUnify}},
+		{"duplicate ID", endingRule{id: RuleVerb, direction: PoliteToCasual}},
+	}
+	for _, tt := range tests {
+		if err := converter.Rules().Add(tt.rule); err == nil {
+			t.Errorf("Add() with %s succeeded, expected an error", tt.name)
+		}
+	}
+
+	// The same ID can be used in the other direction
+	if err := converter.Rules().Add(endingRule{id: "x", direction: PoliteToCasual}); err != nil {
+		t.Errorf("Add() failed: %v", err)
+	}
+	if err := converter.Rules().Add(endingRule{id: "x", direction: CasualToPolite}); err != nil {
+		t.Errorf("Add() failed: %v", err)
+	}
+}
+
+func TestRuleRegistry_Remove(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	if err := converter.Rules().Remove(RuleConjunction); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+	if got, _ := converter.Convert("だから、本を読む。", CasualToPolite); got != "だから、本を読みます。" {
+		t.Errorf("Convert() without the conjunction rule = %q, expected %q", got, "だから、本を読みます。")
+	}
+	if err := converter.Rules().Remove(RuleConjunction); err == nil {
+		t.Error("Remove() of a removed rule succeeded, expected an error")
+	}
+}
+
+func TestRuleRegistry_Negative(t *testing.T) {
+	input := "本を読まない。"
+	slang := endingRule{id: "slang", direction: CasualToPolite, priority: 250, from: "ない", to: "ねえ"}
+
+	converter, err := NewConverter(WithDisabledRules(RulePredicate), WithRule(slang))
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+	// slang rewrites the ending first, so the negative rule is not applied
+	if got, _ := converter.Convert(input, CasualToPolite); got != "本を読まねえ。" {
+		t.Errorf("Convert() = %q, expected %q", got, "本を読まねえ。")
+	}
+
+	if err := converter.Rules().SetPriority(RuleNegative, 260); err != nil {
+		t.Fatalf("SetPriority() failed: %v", err)
+	}
+	result, err := converter.ConvertDetailed(input, CasualToPolite)
+	if err != nil {
+		t.Fatalf("ConvertDetailed() failed: %v", err)
+	}
+	if got := result.Sentences[0].Rules; result.Text != "本を読みません。" || fmt.Sprint(got) != "[negative]" {
+		t.Errorf("ConvertDetailed() = %q by %v, expected %q by [negative]", result.Text, got, "本を読みません。")
+	}
+
+	if err := converter.Rules().Remove(RuleNegative); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+	if err := converter.Rules().Remove(slang.id); err != nil {
+		t.Fatalf("Remove() failed: %v", err)
+	}
+	if got, _ := converter.Convert(input, CasualToPolite); got != input {
+		t.Errorf("Convert() without the negative rule = %q, expected %q", got, input)
+	}
+}
+
+func TestRuleRegistry_Order(t *testing.T) {
+	converter, err := NewConverter(
+		WithRule(endingRule{id: "a", direction: CasualToPolite, priority: 550}),
+		WithRule(endingRule{id: "b", direction: CasualToPolite, priority: 550}),
+	)
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	ids := func() string {
+		var ids []string
+		for _, r := range converter.Rules().Rules(CasualToPolite) {
+			ids = append(ids, r.ID())
+		}
+		return fmt.Sprint(ids)
+	}
+	if got, expected := ids(), "[predicate verb a b adjective copula auxiliary negative conjunction]"; got != expected {
+		t.Errorf("Rules() = %s, expected %s", got, expected)
+	}
+
+	if err := converter.Rules().SetPriority(RuleConjunction, 1000); err != nil {
+		t.Fatalf("SetPriority() failed: %v", err)
+	}
+	if err := converter.Rules().SetPriority("a", 0); err != nil {
+		t.Fatalf("SetPriority() failed: %v", err)
+	}
+	if got, expected := ids(), "[conjunction predicate verb b adjective copula auxiliary negative a]"; got != expected {
+		t.Errorf("Rules() = %s, expected %s", got, expected)
+	}
+	if err := converter.Rules().SetPriority("unknown", 0); err == nil {
+		t.Error("SetPriority() of an unknown rule succeeded, expected an error")
+	}
+}
+
+func TestRuleRegistry_InvalidWindow(t *testing.T) {
+	rule := windowRule{endingRule: endingRule{id: "window", direction: CasualToPolite, priority: 800, to: "x"}, start: 2, end: 100}
+	converter, err := NewConverter(WithRule(rule))
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+	if got, _ := converter.Convert("本を読む。", CasualToPolite); got != "本を読みます。" {
+		t.Errorf("Convert() = %q, expected %q", got, "本を読みます。")
+	}
+}
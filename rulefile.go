@@ -0,0 +1,441 @@
+package kjconv
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// DefaultRuleFilePriority is the priority of the rules of a rule file that do
+// not set one. It is higher than those of the built-in rules, so that the
+// mappings of a rule file take precedence.
+const DefaultRuleFilePriority = 800
+
+// RuleFileError reports an invalid rule file.
+type RuleFileError struct {
+	Name string // ファイル名（LoadRules では空）
+	Line int    // 行番号（1始まり）
+	Err  error
+}
+
+// Error returns the error as "name:line: message".
+func (e *RuleFileError) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s:%d: %v", e.Name, e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RuleFileError) Unwrap() error {
+	return e.Err
+}
+
+// LoadRuleFile reads the rules of a JSON rule file; see LoadRules.
+func LoadRuleFile(name string) ([]Rule, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return parseRules(name, data)
+}
+
+// LoadRules reads the rules of a JSON rule file. The file is an array of
+// mappings between a casual and a polite ending, e.g.
+//
+//	[
+//	  {
+//	    "id": "hazu",
+//	    "casual": [{"surface": "はず"}, {"surface": "だ", "pos": "助動詞"}],
+//	    "polite": [{"surface": "はず"}, {"surface": "です", "pos": "助動詞"}]
+//	  },
+//	  {
+//	    "id": "kamoshirenai",
+//	    "casual": [{"pos": "動詞", "capture": "v"}, {"surface": "かも"}, {"surface": "しれ"}, {"surface": "ない"}],
+//	    "polite": [{"pos": "動詞", "capture": "v"}, {"surface": "かも"}, {"surface": "しれ"}, {"surface": "ませ"}, {"surface": "ん"}]
+//	  }
+//	]
+//
+// Each mapping becomes a rule for CasualToPolite, which rewrites morphemes
+// matching "casual" into "polite", and one for PoliteToCasual, which does the
+// opposite; both have the ID of the mapping.
+//
+// A pattern is a list of morphemes, each matching the fields of MorphemeInfo
+// that it sets: "surface", "pos" (品詞), "posDetail1" (品詞細分類1), "baseForm",
+// "inflectionType" and "inflectionForm". A morpheme with "capture" binds the
+// morpheme it matches to a name, and the morpheme with the same capture in the
+// other pattern is replaced with it, so every capture must appear in both
+// patterns. Other morphemes must have a "surface", which is used when the
+// pattern is the replacement.
+//
+// A pattern matches the end of a sentence, before the final punctuation,
+// unless "position" is "anywhere", in which case it matches the first place in
+// the sentence. "priority" sets the priority of the rules, which is
+// DefaultRuleFilePriority by default.
+//
+// Errors report the line of the rule file as a *RuleFileError.
+func LoadRules(r io.Reader) ([]Rule, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return parseRules("", data)
+}
+
+// WithRuleFile adds the rules of a JSON rule file to the converter's
+// RuleRegistry; see LoadRules.
+func WithRuleFile(name string) Option {
+	return func(c *Converter) error {
+		rules, err := LoadRuleFile(name)
+		if err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			if err := c.rules.Add(rule); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// morphemePattern matches a morpheme of a rule file pattern.
+type morphemePattern struct {
+	Surface             string `json:"surface"`
+	PartOfSpeech        string `json:"pos"`
+	PartOfSpeechDetail1 string `json:"posDetail1"`
+	BaseForm            string `json:"baseForm"`
+	InflectionType      string `json:"inflectionType"`
+	InflectionForm      string `json:"inflectionForm"`
+	Capture             string `json:"capture"`
+}
+
+// matches reports whether the morpheme has every field the pattern sets.
+func (p morphemePattern) matches(m MorphemeInfo) bool {
+	for _, f := range [][2]string{
+		{p.Surface, m.Surface},
+		{p.PartOfSpeech, m.PartOfSpeech},
+		{p.PartOfSpeechDetail1, m.PartOfSpeechDetail1},
+		{p.BaseForm, m.BaseForm},
+		{p.InflectionType, m.InflectionType},
+		{p.InflectionForm, m.InflectionForm},
+	} {
+		if f[0] != "" && f[0] != f[1] {
+			return false
+		}
+	}
+	return true
+}
+
+// generate returns the morpheme for the pattern in a replacement: the captured
+// morpheme, or a new one with the fields of the pattern.
+func (p morphemePattern) generate(captured map[string]MorphemeInfo) MorphemeInfo {
+	if p.Capture != "" {
+		return captured[p.Capture]
+	}
+	return MorphemeInfo{
+		Surface:             p.Surface,
+		PartOfSpeech:        p.PartOfSpeech,
+		PartOfSpeechDetail1: p.PartOfSpeechDetail1,
+		BaseForm:            p.BaseForm,
+		InflectionType:      p.InflectionType,
+		InflectionForm:      p.InflectionForm,
+	}
+}
+
+// patternRule is a rule of a rule file. It rewrites morphemes matching from
+// into to.
+type patternRule struct {
+	id        string
+	direction ConversionMode
+	priority  int
+	anywhere  bool // 文末以外にも一致する
+	from, to  []morphemePattern
+}
+
+func (r *patternRule) ID() string                { return r.id }
+func (r *patternRule) Direction() ConversionMode { return r.direction }
+func (r *patternRule) Priority() int             { return r.priority }
+
+func (r *patternRule) Match(morphemes []MorphemeInfo) (int, int, bool) {
+	if !r.anywhere {
+		end := contentEnd(morphemes)
+		start := end - len(r.from)
+		return start, end, start >= 0 && r.matchesAt(morphemes, start)
+	}
+	for start := 0; start+len(r.from) <= len(morphemes); start++ {
+		if r.matchesAt(morphemes, start) {
+			return start, start + len(r.from), true
+		}
+	}
+	return 0, 0, false
+}
+
+// matchesAt reports whether the pattern matches the morphemes from start.
+func (r *patternRule) matchesAt(morphemes []MorphemeInfo, start int) bool {
+	for i, p := range r.from {
+		if !p.matches(morphemes[start+i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *patternRule) Rewrite(window []MorphemeInfo) []MorphemeInfo {
+	captured := make(map[string]MorphemeInfo)
+	for i, p := range r.from {
+		if p.Capture != "" {
+			captured[p.Capture] = window[i]
+		}
+	}
+	result := make([]MorphemeInfo, len(r.to))
+	for i, p := range r.to {
+		result[i] = p.generate(captured)
+	}
+	return result
+}
+
+// contentEnd returns the index just after the last morpheme that is not a
+// symbol, such as the final punctuation.
+func contentEnd(morphemes []MorphemeInfo) int {
+	end := len(morphemes)
+	for end > 0 && morphemes[end-1].PartOfSpeech == "記号" {
+		end--
+	}
+	return end
+}
+
+// ruleParser parses a rule file and reports errors with their line.
+type ruleParser struct {
+	name  string
+	data  []byte
+	lines *lineCounter
+}
+
+// errorAt returns an error at the byte offset of the file.
+func (p *ruleParser) errorAt(offset int, format string, args ...any) error {
+	line, _ := p.lines.position(offset)
+	return &RuleFileError{Name: p.name, Line: line, Err: fmt.Errorf(format, args...)}
+}
+
+// decodeErrorAt returns the error of decoding the JSON value at the offset.
+func (p *ruleParser) decodeErrorAt(offset int, err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		offset += int(typeErr.Offset)
+	}
+	return p.errorAt(offset, "%v", err)
+}
+
+// parseRules parses the rule file data; name is used in errors.
+func parseRules(name string, data []byte) ([]Rule, error) {
+	p := &ruleParser{name: name, data: data, lines: newLineCounter(string(data))}
+
+	// Check the syntax first, since the decoder reports no position for it
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return nil, p.errorAt(max(int(syntaxErr.Offset)-1, 0), "%v", err)
+		}
+		return nil, p.errorAt(0, "%v", err)
+	}
+	if _, ok := v.([]any); !ok {
+		return nil, p.errorAt(skipSpace(data, 0), "rule file must be an array of rules")
+	}
+
+	var rules []Rule
+	ids := make(map[string]bool)
+	err := decodeArray(data, 0, func(offset int, raw json.RawMessage) error {
+		casual, polite, err := p.parseRule(offset, raw, ids)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, casual, polite)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// parseRule parses the rule at the offset into the rules of both directions.
+func (p *ruleParser) parseRule(offset int, raw json.RawMessage, ids map[string]bool) (*patternRule, *patternRule, error) {
+	if !bytes.HasPrefix(raw, []byte("{")) {
+		return nil, nil, p.errorAt(offset, "rule must be an object")
+	}
+
+	rule := &patternRule{priority: DefaultRuleFilePriority}
+	var casual, polite []morphemePattern
+	hasCasual, hasPolite := false, false
+	err := decodeObject(raw, offset, func(key string, offset int, value json.RawMessage) error {
+		var err error
+		switch key {
+		case "id":
+			err = json.Unmarshal(value, &rule.id)
+		case "priority":
+			err = json.Unmarshal(value, &rule.priority)
+		case "position":
+			var position string
+			if err = json.Unmarshal(value, &position); err == nil {
+				switch position {
+				case "end":
+				case "anywhere":
+					rule.anywhere = true
+				default:
+					return p.errorAt(offset, "unknown position: %q", position)
+				}
+			}
+		case "casual":
+			casual, err = p.parsePattern(offset, value)
+			hasCasual = true
+		case "polite":
+			polite, err = p.parsePattern(offset, value)
+			hasPolite = true
+		default:
+			return p.errorAt(offset, "unknown field: %q", key)
+		}
+		if err != nil {
+			var fileErr *RuleFileError
+			if errors.As(err, &fileErr) {
+				return err
+			}
+			return p.decodeErrorAt(offset, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch {
+	case rule.id == "":
+		return nil, nil, p.errorAt(offset, "rule has no id")
+	case ids[rule.id]:
+		return nil, nil, p.errorAt(offset, "duplicate rule id: %q", rule.id)
+	case !hasCasual || len(casual) == 0:
+		return nil, nil, p.errorAt(offset, "rule %q has no casual pattern", rule.id)
+	case !hasPolite || len(polite) == 0:
+		return nil, nil, p.errorAt(offset, "rule %q has no polite pattern", rule.id)
+	}
+	ids[rule.id] = true
+
+	// Every capture must be replaced in both directions
+	captures := func(patterns []morphemePattern) map[string]bool {
+		names := make(map[string]bool)
+		for _, pattern := range patterns {
+			if pattern.Capture != "" {
+				names[pattern.Capture] = true
+			}
+		}
+		return names
+	}
+	casualCaptures, politeCaptures := captures(casual), captures(polite)
+	for name := range casualCaptures {
+		if !politeCaptures[name] {
+			return nil, nil, p.errorAt(offset, "rule %q: capture %q is not in the polite pattern", rule.id, name)
+		}
+	}
+	for name := range politeCaptures {
+		if !casualCaptures[name] {
+			return nil, nil, p.errorAt(offset, "rule %q: capture %q is not in the casual pattern", rule.id, name)
+		}
+	}
+
+	toPolite, toCasual := *rule, *rule
+	toPolite.direction, toPolite.from, toPolite.to = CasualToPolite, casual, polite
+	toCasual.direction, toCasual.from, toCasual.to = PoliteToCasual, polite, casual
+	return &toPolite, &toCasual, nil
+}
+
+// parsePattern parses the pattern at the offset.
+func (p *ruleParser) parsePattern(offset int, raw json.RawMessage) ([]morphemePattern, error) {
+	if !bytes.HasPrefix(raw, []byte("[")) {
+		return nil, p.errorAt(offset, "pattern must be an array of morphemes")
+	}
+
+	var patterns []morphemePattern
+	captures := make(map[string]bool)
+	err := decodeArray(raw, offset, func(offset int, raw json.RawMessage) error {
+		var pattern morphemePattern
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&pattern); err != nil {
+			return p.decodeErrorAt(offset, err)
+		}
+		switch {
+		case pattern.Capture == "" && pattern.Surface == "":
+			return p.errorAt(offset, "morpheme needs a surface or a capture")
+		case captures[pattern.Capture]:
+			return p.errorAt(offset, "duplicate capture: %q", pattern.Capture)
+		}
+		if pattern.Capture != "" {
+			captures[pattern.Capture] = true
+		}
+		patterns = append(patterns, pattern)
+		return nil
+	})
+	return patterns, err
+}
+
+// decodeArray calls fn with each element of the JSON array data and its
+// offset, where offset is the offset of data in the file.
+func decodeArray(data []byte, offset int, fn func(offset int, element json.RawMessage) error) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		start := skipSpace(data, int(dec.InputOffset()))
+		var element json.RawMessage
+		if err := dec.Decode(&element); err != nil {
+			return err
+		}
+		if err := fn(offset+start, element); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeObject calls fn with each member of the JSON object data and the
+// offset of its value, where offset is the offset of data in the file.
+func decodeObject(data []byte, offset int, fn func(key string, offset int, value json.RawMessage) error) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		start := skipSpace(data, int(dec.InputOffset()))
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		if err := fn(key.(string), offset+start, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipSpace returns the offset of the first byte from offset that is not
+// whitespace or a JSON separator.
+func skipSpace(data []byte, offset int) int {
+	for offset < len(data) {
+		switch data[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
@@ -0,0 +1,185 @@
+package kjconv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testRuleFile = `[
+  {
+    "id": "kamoshirenai",
+    "casual": [{"pos": "動詞", "capture": "v"}, {"surface": "かも"}, {"surface": "しれ"}, {"surface": "ない"}],
+    "polite": [
+      {"pos": "動詞", "capture": "v"},
+      {"surface": "かも"},
+      {"surface": "しれ", "pos": "動詞", "baseForm": "しれる", "inflectionForm": "連用形"},
+      {"surface": "ませ", "pos": "助動詞", "baseForm": "ます"},
+      {"surface": "ん", "pos": "助動詞", "baseForm": "ん"}
+    ]
+  },
+  {
+    "id": "kichinto",
+    "position": "anywhere",
+    "priority": 50,
+    "casual": [{"surface": "ちゃんと"}],
+    "polite": [{"surface": "きちんと", "pos": "副詞"}]
+  }
+]`
+
+func TestLoadRules(t *testing.T) {
+	rules, err := LoadRules(strings.NewReader(testRuleFile))
+	if err != nil {
+		t.Fatalf("LoadRules() failed: %v", err)
+	}
+
+	var got []string
+	for _, r := range rules {
+		got = append(got, fmt.Sprintf("%s/%v/%d", r.ID(), r.Direction(), r.Priority()))
+	}
+	expected := []string{"kamoshirenai/0/800", "kamoshirenai/1/800", "kichinto/0/50", "kichinto/1/50"}
+	if fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("LoadRules() = %v, expected %v", got, expected)
+	}
+}
+
+func TestLoadRules_Convert(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(name, []byte(testRuleFile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	converter, err := NewConverter(WithRuleFile(name))
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		mode     ConversionMode
+		expected string
+		rules    []string
+	}{
+		{"雨が降るかもしれない。", CasualToPolite, "雨が降るかもしれません。", []string{"kamoshirenai"}},
+		{"雨が降るかもしれません。", PoliteToCasual, "雨が降るかもしれない。", []string{"kamoshirenai"}},
+		{"ちゃんと読む。", CasualToPolite, "きちんと読みます。", []string{RulePredicate, "kichinto"}},
+		{"きちんと読みます。", PoliteToCasual, "ちゃんと読む。", []string{RulePredicate, "kichinto"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := converter.ConvertDetailed(tt.input, tt.mode)
+			if err != nil {
+				t.Fatalf("ConvertDetailed() failed: %v", err)
+			}
+			if result.Text != tt.expected {
+				t.Errorf("ConvertDetailed(%q).Text = %q, expected %q", tt.input, result.Text, tt.expected)
+			}
+			if rules := result.Sentences[0].Rules; fmt.Sprint(rules) != fmt.Sprint(tt.rules) {
+				t.Errorf("Rules = %q, expected %q", rules, tt.rules)
+			}
+		})
+	}
+}
+
+func TestLoadRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		line    int
+		message string
+	}{
+		{
+			name:    "syntax",
+			file:    "[\n  {\"id\": \"a\",\n   \"casual\" [] }\n]",
+			line:    3,
+			message: "invalid character",
+		},
+		{
+			name:    "not an array",
+			file:    "\n{\"id\": \"a\"}",
+			line:    2,
+			message: "must be an array",
+		},
+		{
+			name:    "unknown field",
+			file:    "[\n  {\n    \"id\": \"a\",\n    \"casaul\": []\n  }\n]",
+			line:    4,
+			message: `unknown field: "casaul"`,
+		},
+		{
+			name:    "wrong type",
+			file:    "[\n  {\n    \"id\": \"a\",\n    \"priority\": \"high\"\n  }\n]",
+			line:    4,
+			message: "cannot unmarshal string",
+		},
+		{
+			name:    "unknown position",
+			file:    "[{\"id\": \"a\",\n \"position\": \"start\"}]",
+			line:    2,
+			message: `unknown position: "start"`,
+		},
+		{
+			name:    "no id",
+			file:    "[\n  {\"casual\": [{\"surface\": \"だ\"}], \"polite\": [{\"surface\": \"です\"}]}\n]",
+			line:    2,
+			message: "no id",
+		},
+		{
+			name:    "missing pattern",
+			file:    "[\n  {\"id\": \"a\", \"casual\": [{\"surface\": \"だ\"}]}\n]",
+			line:    2,
+			message: "no polite pattern",
+		},
+		{
+			name:    "unknown morpheme field",
+			file:    "[{\"id\": \"a\",\n  \"casual\": [\n    {\"surface\": \"だ\"},\n    {\"surfase\": \"よ\"}\n  ],\n  \"polite\": [{\"surface\": \"です\"}]}]",
+			line:    4,
+			message: `unknown field "surfase"`,
+		},
+		{
+			name:    "morpheme without surface",
+			file:    "[{\"id\": \"a\",\n  \"casual\": [{\"surface\": \"だ\"}],\n  \"polite\": [{\"pos\": \"助動詞\"}]}]",
+			line:    3,
+			message: "needs a surface or a capture",
+		},
+		{
+			name:    "capture in one pattern",
+			file:    "[\n  {\"id\": \"a\", \"casual\": [{\"capture\": \"x\"}], \"polite\": [{\"surface\": \"です\"}]}\n]",
+			line:    2,
+			message: `capture "x" is not in the polite pattern`,
+		},
+		{
+			name:    "duplicate id",
+			file:    "[\n  {\"id\": \"a\", \"casual\": [{\"surface\": \"だ\"}], \"polite\": [{\"surface\": \"です\"}]},\n  {\"id\": \"a\", \"casual\": [{\"surface\": \"だ\"}], \"polite\": [{\"surface\": \"です\"}]}\n]",
+			line:    3,
+			message: `duplicate rule id: "a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadRules(strings.NewReader(tt.file))
+			var fileErr *RuleFileError
+			if !errors.As(err, &fileErr) {
+				t.Fatalf("LoadRules() error = %v, expected a *RuleFileError", err)
+			}
+			if fileErr.Line != tt.line || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("LoadRules() error = %q at line %d, expected %q at line %d", err, fileErr.Line, tt.message, tt.line)
+			}
+		})
+	}
+}
+
+func TestLoadRuleFile_ErrorName(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(name, []byte("[\n  {}\n]"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := LoadRuleFile(name)
+	if err == nil || !strings.HasPrefix(err.Error(), name+":2: ") {
+		t.Errorf("LoadRuleFile() error = %v, expected it to start with %q", err, name+":2: ")
+	}
+}
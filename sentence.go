@@ -1,65 +1,125 @@
 package kjconv
 
 import (
-	"regexp"
 	"strings"
+	"unicode"
 )
 
+// Segment is a sentence together with the whitespace around it in the source text.
+// Concatenating Leading, Text and Trailing of all segments returned by
+// SplitSegments or SplitLines reproduces the input byte-for-byte.
+type Segment struct {
+	Leading  string // Text の直前の空白
+	Text     string // 文（前後の空白を除く）
+	Trailing string // Text の直後の空白・改行
+	Offset   int    // 入力中の Text の開始位置（バイト）
+}
+
 // SplitSentences splits text into sentences based on sentence-ending punctuation.
-// It splits on 句点（。）, 疑問符（？）, and 感嘆符（！）.
+// It splits on 句点（。）, 疑問符（？）, and 感嘆符（！）, as well as the other
+// terminators in DefaultTerminators (?!．…, half-width periods and line ends).
+// Whitespace around sentences is removed; use SplitSegments to keep it.
 func SplitSentences(text string) []string {
-	// Regular expression to split on sentence-ending punctuation
-	// Matches 。, ？, ！ followed by optional whitespace
-	re := regexp.MustCompile(`[。？！]\s*`)
-	
-	// Find all matches to preserve the punctuation
-	matches := re.FindAllStringIndex(text, -1)
-	
-	if len(matches) == 0 {
-		// No sentence-ending punctuation found
-		trimmed := strings.TrimSpace(text)
-		if trimmed == "" {
-			return []string{}
+	sentences := []string{}
+	for _, segment := range SplitSegments(text) {
+		if segment.Text != "" {
+			sentences = append(sentences, segment.Text)
 		}
-		return []string{trimmed}
 	}
-	
-	var sentences []string
+	return sentences
+}
+
+// SplitSegments splits text into sentences like SplitSentences, but keeps the
+// whitespace between sentences (spaces, full-width spaces, newlines including
+// CRLF) in the Leading and Trailing fields of each segment.
+// Sentences are never split inside a bracket pair such as 「…。…」 or （…。…）.
+// It uses DefaultTerminators; see Terminators.SplitSegments.
+func SplitSegments(text string) []Segment {
+	return DefaultTerminators().SplitSegments(text)
+}
+
+// Segmentation selects how text is divided into units for conversion.
+type Segmentation int
+
+const (
+	// SegmentSentences splits text into sentences at terminators. This is the default.
+	SegmentSentences Segmentation = iota
+	// SegmentLines ends a unit at every line break, even when the line has no
+	// terminator, and still splits each line at its terminators. It suits
+	// bullet lists, table cells and chat logs.
+	SegmentLines
+)
+
+// SetSegmentation sets how the input of Convert is divided into units.
+func (c *Converter) SetSegmentation(s Segmentation) {
+	c.segmentation = s
+}
+
+// SplitLines splits text into lines. Each non-empty line becomes one segment
+// whose Text is the line without indentation and line break, which are kept in
+// Leading and Trailing. Empty lines become segments with an empty Text.
+func SplitLines(text string) []Segment {
+	var segments []Segment
 	start := 0
-	
-	for _, match := range matches {
-		end := match[1] // Include the punctuation and any following whitespace
-		sentence := strings.TrimSpace(text[start:end])
-		if sentence != "" {
-			sentences = append(sentences, sentence)
+	for start < len(text) {
+		end := len(text)
+		if i := strings.IndexByte(text[start:], '\n'); i >= 0 {
+			end = start + i + 1
 		}
+		segments = append(segments, newSegment(text[start:end], start))
 		start = end
 	}
-	
-	// Add any remaining text after the last punctuation
-	if start < len(text) {
-		remaining := strings.TrimSpace(text[start:])
-		if remaining != "" {
-			sentences = append(sentences, remaining)
+	return segments
+}
+
+// splitSegments splits text into units according to the converter's segmentation.
+func (c *Converter) splitSegments(text string) []Segment {
+	if c.segmentation != SegmentLines {
+		return c.terminators.SplitSegments(text)
+	}
+
+	var segments []Segment
+	for _, line := range SplitLines(text) {
+		sentences := c.terminators.SplitSegments(line.Text)
+		if len(sentences) == 0 {
+			segments = append(segments, line)
+			continue
 		}
+		for i := range sentences {
+			sentences[i].Offset += line.Offset
+		}
+		// The indentation and the line break stay around the sentences of the line
+		sentences[0].Leading = line.Leading + sentences[0].Leading
+		sentences[len(sentences)-1].Trailing += line.Trailing
+		segments = append(segments, sentences...)
 	}
+	return segments
+}
+
+// newSegment creates a segment from a piece of text starting at offset.
+func newSegment(piece string, offset int) Segment {
+	trimmed := strings.TrimLeftFunc(piece, unicode.IsSpace)
+	leading := piece[:len(piece)-len(trimmed)]
+	text := strings.TrimRightFunc(trimmed, unicode.IsSpace)
 	
-	return sentences
+	return Segment{
+		Leading:  leading,
+		Text:     text,
+		Trailing: trimmed[len(text):],
+		Offset:   offset + len(leading),
+	}
 }
 
 // IsQuotedText checks if the text is within quotation marks (「」or『』).
 // Quoted text should not be converted according to the specification.
+// The text is quoted only when the opening mark at its start is closed by the
+// mark at its end, so 「a」と「b」 is not quoted text.
 func IsQuotedText(text string) bool {
-	// Check for 「」quotation marks
-	if strings.HasPrefix(text, "「") && strings.HasSuffix(text, "」") {
-		return true
-	}
-	
-	// Check for 『』quotation marks
-	if strings.HasPrefix(text, "『") && strings.HasSuffix(text, "』") {
-		return true
+	for _, span := range scanBrackets(text) {
+		if span.Start == 0 {
+			return span.isQuote() && span.End == len(text)
+		}
 	}
-	
 	return false
 }
 
@@ -70,56 +130,53 @@ func ContainsQuotedText(text string) bool {
 }
 
 // ProcessTextWithQuotes processes text while preserving quoted sections.
+// Quotations may be nested at any depth (e.g. 「彼は『行く』と言った」) and may
+// appear inside other brackets; the outermost quotation is kept as-is.
 func ProcessTextWithQuotes(text string, processor func(string) (string, error)) (string, error) {
+	result, _, err := processTextWithQuotes(text, func(segment string) (string, []TextEdit, error) {
+		processed, err := processor(segment)
+		return processed, nil, err
+	})
+	return result, err
+}
+
+// processTextWithQuotes is ProcessTextWithQuotes for processors that report
+// their changes as edits. The edits are returned against text.
+func processTextWithQuotes(text string, processor func(string) (string, []TextEdit, error)) (string, []TextEdit, error) {
 	if !ContainsQuotedText(text) {
 		return processor(text)
 	}
 	
-	var result strings.Builder
-	var currentSegment strings.Builder
-	var inQuote bool
-	var quoteChar rune
+	quotes := outermostSpans(scanBrackets(text), bracketSpan.isQuote)
 	
-	runes := []rune(text)
-	for _, r := range runes {
-		if !inQuote && (r == '「' || r == '『') {
-			// Process the segment before the quote
-			if currentSegment.Len() > 0 {
-				processed, err := processor(currentSegment.String())
-				if err != nil {
-					return "", err
-				}
-				result.WriteString(processed)
-				currentSegment.Reset()
+	var result strings.Builder
+	var edits []TextEdit
+	start := 0
+	for _, quote := range quotes {
+		// Process the segment before the quote
+		if quote.Start > start {
+			processed, segmentEdits, err := processor(text[start:quote.Start])
+			if err != nil {
+				return "", nil, err
 			}
-			
-			// Start quote
-			inQuote = true
-			quoteChar = r
-			result.WriteRune(r)
-		} else if inQuote && ((r == '」' && quoteChar == '「') || (r == '』' && quoteChar == '『')) {
-			// End quote - add the quoted content as-is
-			result.WriteString(currentSegment.String())
-			result.WriteRune(r)
-			currentSegment.Reset()
-			inQuote = false
-		} else if inQuote {
-			// Inside quote - collect without processing
-			currentSegment.WriteRune(r)
-		} else {
-			// Outside quote - collect for processing
-			currentSegment.WriteRune(r)
+			result.WriteString(processed)
+			edits = append(edits, shiftEdits(segmentEdits, start)...)
 		}
+		
+		// Add the quoted content as-is
+		result.WriteString(text[quote.Start:quote.End])
+		start = quote.End
 	}
 	
 	// Process any remaining segment
-	if currentSegment.Len() > 0 {
-		processed, err := processor(currentSegment.String())
+	if start < len(text) {
+		processed, segmentEdits, err := processor(text[start:])
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 		result.WriteString(processed)
+		edits = append(edits, shiftEdits(segmentEdits, start)...)
 	}
 	
-	return result.String(), nil
+	return result.String(), edits, nil
 }
@@ -56,6 +56,26 @@ func TestSplitSentences(t *testing.T) {
 			input:    "今日は晴れです。  明日は雨です。",
 			expected: []string{"今日は晴れです。", "明日は雨です。"},
 		},
+		{
+			name:     "引用内の句点では分割しない",
+			input:    "彼は「今日は晴れだ。明日は雨だ。」と言った。本を読む。",
+			expected: []string{"彼は「今日は晴れだ。明日は雨だ。」と言った。", "本を読む。"},
+		},
+		{
+			name:     "入れ子の括弧",
+			input:    "彼は「『行く！』と言え。」と言った。次の文。",
+			expected: []string{"彼は「『行く！』と言え。」と言った。", "次の文。"},
+		},
+		{
+			name:     "丸括弧内の句点",
+			input:    "本を読む（毎日。）。次の文。",
+			expected: []string{"本を読む（毎日。）。", "次の文。"},
+		},
+		{
+			name:     "閉じられていない括弧は無視する",
+			input:    "「今日は晴れだ。明日は雨だ。",
+			expected: []string{"「今日は晴れだ。", "明日は雨だ。"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -68,6 +88,108 @@ func TestSplitSentences(t *testing.T) {
 	}
 }
 
+func TestSplitSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Segment
+	}{
+		{
+			name:  "空白なし",
+			input: "今日は晴れです。明日は雨です。",
+			expected: []Segment{
+				{Text: "今日は晴れです。", Offset: 0},
+				{Text: "明日は雨です。", Offset: 24},
+			},
+		},
+		{
+			name:  "改行と字下げ",
+			input: "  今日は晴れです。\n\n　明日は雨です。\n",
+			expected: []Segment{
+				{Leading: "  ", Text: "今日は晴れです。", Trailing: "\n\n　", Offset: 2},
+				{Text: "明日は雨です。", Trailing: "\n", Offset: 31},
+			},
+		},
+		{
+			name:  "CRLF",
+			input: "今日は晴れです。\r\n明日は雨です",
+			expected: []Segment{
+				{Text: "今日は晴れです。", Trailing: "\r\n", Offset: 0},
+				{Text: "明日は雨です", Offset: 26},
+			},
+		},
+		{
+			name:  "空白のみ",
+			input: " \n",
+			expected: []Segment{
+				{Leading: " \n", Offset: 2},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SplitSegments(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("SplitSegments(%q) = %#v, expected %#v", tt.input, result, tt.expected)
+			}
+
+			var joined string
+			for _, segment := range result {
+				joined += segment.Leading + segment.Text + segment.Trailing
+				if segment.Text != "" && tt.input[segment.Offset:segment.Offset+len(segment.Text)] != segment.Text {
+					t.Errorf("Offset %d does not point at %q", segment.Offset, segment.Text)
+				}
+			}
+			if joined != tt.input {
+				t.Errorf("joined segments = %q, expected %q", joined, tt.input)
+			}
+		})
+	}
+}
+
+func TestSplitLines(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []Segment
+	}{
+		{
+			name:  "箇条書き",
+			input: "- 本を読む\n- 字を書く",
+			expected: []Segment{
+				{Text: "- 本を読む", Trailing: "\n", Offset: 0},
+				{Text: "- 字を書く", Offset: 15},
+			},
+		},
+		{
+			name:  "行内の句点では分割しない",
+			input: "晴れだ。本を読む\r\n",
+			expected: []Segment{
+				{Text: "晴れだ。本を読む", Trailing: "\r\n", Offset: 0},
+			},
+		},
+		{
+			name:  "空行と字下げ",
+			input: "　本を読む\n\n字を書く",
+			expected: []Segment{
+				{Leading: "　", Text: "本を読む", Trailing: "\n", Offset: 3},
+				{Leading: "\n", Offset: 17},
+				{Text: "字を書く", Offset: 17},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := SplitLines(tt.input)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("SplitLines(%q) = %#v, expected %#v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestIsQuotedText(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -114,6 +236,21 @@ func TestIsQuotedText(t *testing.T) {
 			input:    "「」",
 			expected: true,
 		},
+		{
+			name:     "入れ子の引用",
+			input:    "「彼は『行く』と言った」",
+			expected: true,
+		},
+		{
+			name:     "複数文の引用",
+			input:    "「今日は晴れだ。明日は雨だ。」",
+			expected: true,
+		},
+		{
+			name:     "先頭と末尾が別の引用",
+			input:    "「今日は晴れだ」と「明日は雨だ」",
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -135,7 +272,8 @@ func TestProcessTextWithQuotes(t *testing.T) {
 
 	// Processor that uses actual conversion logic
 	processor := func(text string) (string, error) {
-		return converter.convertCasualToPoliteSegment(text)
+		converted, _, err := converter.convertCasualToPoliteSegment(text, nil)
+		return converted, err
 	}
 
 	tests := []struct {
@@ -168,6 +306,21 @@ func TestProcessTextWithQuotes(t *testing.T) {
 			input:    "昨日彼は「今日は晴れだ」と言った。",
 			expected: "昨日彼は「今日は晴れだ」と言いました。",
 		},
+		{
+			name:     "入れ子の引用文",
+			input:    "彼女は「彼は『行く』と言った」と言った。",
+			expected: "彼女は「彼は『行く』と言った」と言いました。",
+		},
+		{
+			name:     "同じ種類の括弧の入れ子",
+			input:    "彼女は「彼は「行く」と言った」と言った。",
+			expected: "彼女は「彼は「行く」と言った」と言いました。",
+		},
+		{
+			name:     "丸括弧内の引用文",
+			input:    "（彼は「行く」と言った）と書く。",
+			expected: "（彼は「行く」と言った）と書きます。",
+		},
 	}
 
 	for _, tt := range tests {
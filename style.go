@@ -0,0 +1,82 @@
+package kjconv
+
+import (
+	"fmt"
+)
+
+// Style holds the preferred expressions where the polite register has more
+// than one. The zero value is the default style.
+type Style struct {
+	AdjectiveNegative AdjectiveNegativeStyle // 美しくありません / 美しくないです
+	Copula            CopulaStyle            // である → です / であります
+}
+
+// AdjectiveNegativeStyle selects the polite negative of i-adjectives.
+type AdjectiveNegativeStyle int
+
+const (
+	// AdjectiveNegativeArimasen uses くありません (美しくありません). This is the default.
+	AdjectiveNegativeArimasen AdjectiveNegativeStyle = iota
+	// AdjectiveNegativeNaiDesu uses くないです (美しくないです).
+	AdjectiveNegativeNaiDesu
+)
+
+// String returns a human readable name of the style.
+func (s AdjectiveNegativeStyle) String() string {
+	switch s {
+	case AdjectiveNegativeArimasen:
+		return "arimasen"
+	case AdjectiveNegativeNaiDesu:
+		return "nai-desu"
+	default:
+		return "unknown"
+	}
+}
+
+// CopulaStyle selects the polite form of the written copula である.
+type CopulaStyle int
+
+const (
+	// CopulaDesu converts である to です (学者です). This is the default.
+	CopulaDesu CopulaStyle = iota
+	// CopulaDearimasu converts である to であります (学者であります).
+	CopulaDearimasu
+)
+
+// String returns a human readable name of the style.
+func (s CopulaStyle) String() string {
+	switch s {
+	case CopulaDesu:
+		return "desu"
+	case CopulaDearimasu:
+		return "dearimasu"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseAdjectiveNegativeStyle returns the style named s ("arimasen" or "nai-desu").
+func ParseAdjectiveNegativeStyle(s string) (AdjectiveNegativeStyle, error) {
+	for _, style := range []AdjectiveNegativeStyle{AdjectiveNegativeArimasen, AdjectiveNegativeNaiDesu} {
+		if s == style.String() {
+			return style, nil
+		}
+	}
+	return AdjectiveNegativeArimasen, fmt.Errorf("unknown adjective negative style: %q", s)
+}
+
+// ParseCopulaStyle returns the style named s ("desu" or "dearimasu").
+func ParseCopulaStyle(s string) (CopulaStyle, error) {
+	for _, style := range []CopulaStyle{CopulaDesu, CopulaDearimasu} {
+		if s == style.String() {
+			return style, nil
+		}
+	}
+	return CopulaDesu, fmt.Errorf("unknown copula style: %q", s)
+}
+
+// SetStyle sets the preferred polite expressions used when converting to the
+// polite register.
+func (c *Converter) SetStyle(s Style) {
+	c.style = s
+}
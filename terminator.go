@@ -0,0 +1,249 @@
+package kjconv
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Terminators configures which characters end a sentence.
+type Terminators struct {
+	// Punctuation lists the characters that end a sentence, e.g. "。？！?!．".
+	Punctuation string
+	// Ellipsis ends a sentence at … and ‥.
+	Ellipsis bool
+	// ASCIIPeriod ends a sentence at a half-width period. Periods in decimal
+	// numbers (3.14), file names and URLs, and Abbreviations are not terminators.
+	ASCIIPeriod bool
+	// LineBreak ends a sentence at the end of a line without punctuation. It
+	// is off by default, so that a sentence may be wrapped over several lines;
+	// SegmentLines converts each line as a unit instead.
+	LineBreak bool
+	// Abbreviations lists words ending with a period that do not end a sentence.
+	Abbreviations []string
+}
+
+// DefaultTerminators returns the terminators used by SplitSentences and NewConverter.
+func DefaultTerminators() Terminators {
+	return Terminators{
+		Punctuation: "。｡．？！?!",
+		Ellipsis:    true,
+		ASCIIPeriod: true,
+		Abbreviations: []string{
+			"e.g.", "i.e.", "etc.", "cf.", "vs.", "approx.", "no.",
+			"mr.", "mrs.", "ms.", "dr.", "prof.", "jr.", "sr.", "st.",
+			"inc.", "ltd.", "co.", "a.m.", "p.m.", "u.s.",
+		},
+	}
+}
+
+// SetTerminators sets the terminators used to split the input of Convert into sentences.
+func (c *Converter) SetTerminators(t Terminators) {
+	c.terminators = t
+}
+
+// SplitSegments splits text into segments like the package-level SplitSegments
+// using the terminators t.
+//
+// A run of terminators such as ！？ or …… ends a single sentence, and decorations
+// that follow it (emoji, kaomoji and （笑）) stay with that sentence.
+func (t Terminators) SplitSegments(text string) []Segment {
+	brackets := outermostSpans(scanBrackets(text), func(bracketSpan) bool { return true })
+
+	var segments []Segment
+	start := 0
+
+	for i := 0; i < len(text); {
+		// Skip bracketed text as a whole
+		if len(brackets) > 0 && i == brackets[0].Start {
+			i = brackets[0].End
+			brackets = brackets[1:]
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case r == '\n' && t.LineBreak:
+			if strings.TrimSpace(text[start:i]) == "" {
+				i += size
+				continue
+			}
+		case t.isTerminator(text, i):
+			i += size
+			// Include the rest of the run and any decorations after it
+			for i < len(text) {
+				if t.isTerminator(text, i) || isEmoji(text, i) {
+					_, size := utf8.DecodeRuneInString(text[i:])
+					i += size
+				} else if len(brackets) > 0 && i == brackets[0].Start && isDecoration(text[brackets[0].Start:brackets[0].End]) {
+					i = brackets[0].End
+					brackets = brackets[1:]
+				} else {
+					break
+				}
+			}
+		default:
+			i += size
+			continue
+		}
+
+		// Include any following whitespace
+		for i < len(text) {
+			r, size := utf8.DecodeRuneInString(text[i:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			i += size
+		}
+
+		segments = append(segments, newSegment(text[start:i], start))
+		start = i
+	}
+
+	if start < len(text) {
+		segments = append(segments, newSegment(text[start:], start))
+	}
+
+	return segments
+}
+
+// isTerminator reports whether the character at text[i] ends a sentence.
+func (t Terminators) isTerminator(text string, i int) bool {
+	r, size := utf8.DecodeRuneInString(text[i:])
+	switch {
+	case strings.ContainsRune(t.Punctuation, r):
+		return true
+	case r == '…' || r == '‥':
+		return t.Ellipsis
+	case r == '.':
+		return t.ASCIIPeriod && t.isPeriodTerminator(text, i, size)
+	}
+	return false
+}
+
+// isPeriodTerminator reports whether the half-width period at text[i] ends a
+// sentence. It does not when it is followed by a letter or digit (3.14,
+// example.com, file.txt), ends one of the abbreviations (e.g.), ends the
+// number of a numbered list (1. at the start of a line) or is between ASCII
+// words (Ver. 2).
+func (t Terminators) isPeriodTerminator(text string, i, size int) bool {
+	if next, _ := utf8.DecodeRuneInString(text[i+size:]); next < utf8.RuneSelf && isASCIIWordChar(next) {
+		return false
+	}
+	if isListNumber(text, i) {
+		return false
+	}
+	prev, _ := utf8.DecodeLastRuneInString(text[:i])
+	next, _ := utf8.DecodeRuneInString(strings.TrimLeft(text[i+size:], " "))
+	if prev < utf8.RuneSelf && isASCIIWordChar(prev) && next < utf8.RuneSelf && isASCIIWordChar(next) {
+		return false
+	}
+
+	// Find the word ending with this period
+	start := i
+	for start > 0 {
+		prev, n := utf8.DecodeLastRuneInString(text[:start])
+		if prev != '.' && !(prev < utf8.RuneSelf && unicode.IsLetter(prev)) {
+			break
+		}
+		start -= n
+	}
+	word := strings.ToLower(text[start : i+size])
+	for _, abbr := range t.Abbreviations {
+		if word == strings.ToLower(abbr) {
+			return false
+		}
+	}
+	return true
+}
+
+// isListNumber reports whether the period at text[i] follows the number of a
+// numbered list, that is, digits at the start of a line after any indentation.
+func isListNumber(text string, i int) bool {
+	start := i
+	for start > 0 && text[start-1] >= '0' && text[start-1] <= '9' {
+		start--
+	}
+	if start == i {
+		return false
+	}
+	line := strings.LastIndexByte(text[:start], '\n') + 1
+	return strings.Trim(text[line:start], " \t") == ""
+}
+
+// isASCIIWordChar reports whether r can be part of a file name, number or URL.
+func isASCIIWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '/'
+}
+
+// isEmoji reports whether the character at text[i] is an emoji or a similar
+// symbol (☆, ♪) including joiners, variation selectors and skin tone modifiers.
+func isEmoji(text string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(text[i:])
+	switch r {
+	case '\u200d', '\ufe0f', '\u20e3': // ZWJ, variation selector, keycap
+		return true
+	}
+	return (unicode.Is(unicode.So, r) && r >= 0x2600) || (unicode.Is(unicode.Sk, r) && r > 0xffff)
+}
+
+// decorationWords are the words that make up a decoration in brackets, e.g. （笑）.
+var decorationWords = []string{"笑", "爆笑", "苦笑", "泣", "涙", "汗", "怒", "照", "謎", "嘘"}
+
+// isDecoration reports whether the bracketed text is a decoration such as （笑）
+// or a kaomoji such as (^_^), rather than part of the sentence.
+func isDecoration(bracketed string) bool {
+	runes := []rune(bracketed)
+	if len(runes) < 3 {
+		return false
+	}
+	content := string(runes[1 : len(runes)-1])
+	for _, word := range decorationWords {
+		if content == word {
+			return true
+		}
+	}
+
+	// Kaomoji consist of symbols and do not contain kana, kanji or digits
+	hasSymbol := false
+	for _, r := range content {
+		switch {
+		case unicode.In(r, unicode.Han, unicode.Hiragana) || unicode.IsDigit(r):
+			return false
+		case unicode.In(r, unicode.Katakana) && r != '・':
+			return false
+		case unicode.IsSymbol(r) || unicode.IsPunct(r):
+			hasSymbol = true
+		}
+	}
+	return hasSymbol
+}
+
+// cutDecoration splits the sentence into the body and the tail made of the
+// terminators and decorations (emoji, kaomoji, （笑）) at its end, so that the
+// predicate can be found at the end of the body. Terminators that the tokenizer
+// does not know as symbols, such as a half-width !, are cut off as well.
+// The tail is empty when the sentence does not end with a terminator or
+// decoration, and the body is empty when the sentence has nothing else (！？(^_^)).
+func (t Terminators) cutDecoration(sentence string) (body, tail string) {
+	end := len(sentence)
+
+loop:
+	for end > 0 {
+		r, size := utf8.DecodeLastRuneInString(sentence[:end])
+		switch {
+		case t.isTerminator(sentence, end-size) || isEmoji(sentence, end-size) || unicode.IsSpace(r):
+			end -= size
+		case r == '）' || r == ')':
+			open := strings.LastIndexAny(sentence[:end], "（(")
+			if open < 0 || !isDecoration(sentence[open:end]) {
+				break loop
+			}
+			end = open
+		default:
+			break loop
+		}
+	}
+
+	return sentence[:end], sentence[end:]
+}
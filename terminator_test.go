@@ -0,0 +1,240 @@
+package kjconv
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTerminators_SplitSegments(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{
+			name:     "半角の疑問符・感嘆符",
+			input:    "行く?来る!帰る。",
+			expected: []string{"行く?", "来る!", "帰る。"},
+		},
+		{
+			name:     "全角ピリオド",
+			input:    "本を読む．字を書く．",
+			expected: []string{"本を読む．", "字を書く．"},
+		},
+		{
+			name:     "半角ピリオド",
+			input:    "本を読む. 字を書く.",
+			expected: []string{"本を読む.", "字を書く."},
+		},
+		{
+			name:     "小数は分割しない",
+			input:    "円周率は3.14だ。次の文。",
+			expected: []string{"円周率は3.14だ。", "次の文。"},
+		},
+		{
+			name:     "略語は分割しない",
+			input:    "e.g. のように書く. 次の文.",
+			expected: []string{"e.g. のように書く.", "次の文."},
+		},
+		{
+			name:     "番号付きリストの番号は分割しない",
+			input:    "1. 本を読む。\n2. 字を書く。",
+			expected: []string{"1. 本を読む。", "2. 字を書く。"},
+		},
+		{
+			name:     "英字の間のピリオドは分割しない",
+			input:    "Ver. 2だ。Fig. 3を見る。",
+			expected: []string{"Ver. 2だ。", "Fig. 3を見る。"},
+		},
+		{
+			name:     "ファイル名やURLは分割しない",
+			input:    "config.yamlを読む。",
+			expected: []string{"config.yamlを読む。"},
+		},
+		{
+			name:     "三点リーダー",
+			input:    "そうか……わかった。",
+			expected: []string{"そうか……", "わかった。"},
+		},
+		{
+			name:     "！？の連続",
+			input:    "本当に行く！？行く。",
+			expected: []string{"本当に行く！？", "行く。"},
+		},
+		{
+			name:     "文中の改行では分割しない",
+			input:    "今日は\n晴れだ。明日は雨だ。",
+			expected: []string{"今日は\n晴れだ。", "明日は雨だ。"},
+		},
+		{
+			name:     "文末の絵文字",
+			input:    "行く！😊 来る。",
+			expected: []string{"行く！😊", "来る。"},
+		},
+		{
+			name:     "文末の（笑）",
+			input:    "行く。（笑）来る。",
+			expected: []string{"行く。（笑）", "来る。"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result []string
+			for _, segment := range DefaultTerminators().SplitSegments(tt.input) {
+				if segment.Text != "" {
+					result = append(result, segment.Text)
+				}
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("SplitSegments(%q) = %q, expected %q", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestTerminators_LineBreak(t *testing.T) {
+	terminators := DefaultTerminators()
+	terminators.LineBreak = true
+	var result []string
+	for _, segment := range terminators.SplitSegments("本を読む\n字を書く\n") {
+		result = append(result, segment.Text)
+	}
+	expected := []string{"本を読む", "字を書く"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("SplitSegments() = %q, expected %q", result, expected)
+	}
+
+	// The number of each item stays with the item
+	result = nil
+	for _, segment := range terminators.SplitSegments("1. 本を読む\n  2. 学生だ") {
+		result = append(result, segment.Text)
+	}
+	expected = []string{"1. 本を読む", "2. 学生だ"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("SplitSegments() = %q, expected %q", result, expected)
+	}
+}
+
+func TestConvert_ListNumberAndAbbreviation(t *testing.T) {
+	converter, err := NewConverter(WithNounEndingPolicy(NounEndingAppend, NounEndingAppend))
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1. 本を読む。", "1. 本を読みます。"},
+		{"Ver. 2だ。", "Ver. 2です。"},
+		{"3. 学生だ。\n4. 字を書く。", "3. 学生です。\n4. 字を書きます。"},
+	}
+	for _, tt := range tests {
+		result, err := converter.Convert(tt.input, CasualToPolite)
+		if err != nil {
+			t.Fatalf("Convert() failed: %v", err)
+		}
+		if result != tt.expected {
+			t.Errorf("Convert(%q) = %q, expected %q", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestTerminators_Disabled(t *testing.T) {
+	terminators := Terminators{Punctuation: "。"}
+	var result []string
+	for _, segment := range terminators.SplitSegments("行く!来る\n帰る…寝る。") {
+		result = append(result, segment.Text)
+	}
+	expected := []string{"行く!来る\n帰る…寝る。"}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("SplitSegments() = %q, expected %q", result, expected)
+	}
+}
+
+func TestConvert_TrailingDecoration(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "（笑）",
+			input:    "明日は学校に行く（笑）",
+			expected: "明日は学校に行きます（笑）",
+		},
+		{
+			name:     "句点の後の（笑）",
+			input:    "明日は学校に行く。（笑）",
+			expected: "明日は学校に行きます。（笑）",
+		},
+		{
+			name:     "絵文字",
+			input:    "本を読む😊",
+			expected: "本を読みます😊",
+		},
+		{
+			name:     "顔文字",
+			input:    "本を読む！(^_^)",
+			expected: "本を読みます！(^_^)",
+		},
+		{
+			name:     "半角の感嘆符",
+			input:    "今日は晴れだ!",
+			expected: "今日は晴れです!",
+		},
+		{
+			name:     "文中の改行",
+			input:    "今日は\n晴れだ。",
+			expected: "今日は\n晴れです。",
+		},
+		{
+			name:     "括弧内の補足は装飾ではない",
+			input:    "本を読む（毎日）。",
+			expected: "本を読む（毎日）。",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := converter.Convert(tt.input, CasualToPolite)
+			if err != nil {
+				t.Errorf("Convert() failed: %v", err)
+				return
+			}
+			if result != tt.expected {
+				t.Errorf("Convert() = %q, expected %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvert_DecorationOnly(t *testing.T) {
+	converter, err := NewConverter(WithNounEndingPolicy(NounEndingAppend, NounEndingAppend))
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	// A sentence of terminators and a kaomoji has no body to convert
+	if body, tail := converter.terminators.cutDecoration("！？(^_^)"); body != "" || tail != "！？(^_^)" {
+		t.Errorf("cutDecoration() = %q, %q, expected %q, %q", body, tail, "", "！？(^_^)")
+	}
+	for _, input := range []string{"！？(^_^)", "本を読む。\n\n！？(^_^)"} {
+		for _, mode := range []ConversionMode{CasualToPolite, PoliteToCasual} {
+			result, err := converter.ConvertDetailed(input, mode)
+			if err != nil {
+				t.Fatalf("ConvertDetailed() failed: %v", err)
+			}
+			last := result.Sentences[len(result.Sentences)-1]
+			if last.Converted != "！？(^_^)" || len(result.Warnings) != 0 {
+				t.Errorf("ConvertDetailed(%q) = %q with %v, expected %q unchanged", input, last.Converted, result.Warnings, "！？(^_^)")
+			}
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package kjconv
+
+// WarningReason identifies why a sentence produced a warning.
+type WarningReason int
+
+const (
+	// WarningInversion is reported for inverted sentences (倒置法) such as 行くよ、明日は。
+	WarningInversion WarningReason = iota
+	// WarningNoRuleMatched is reported when no conversion rule matched the
+	// ending of a sentence with a predicate. Sentences without one, such as
+	// え… and ありがとう, are not reported.
+	WarningNoRuleMatched
+	// WarningNounEnding is reported for 体言止め when NounEndingWarn is in effect.
+	WarningNounEnding
+	// WarningLiteraryEnding is reported for sentences ending with a literary or
+	// classical (文語) inflection, which are left unchanged.
+	WarningLiteraryEnding
+)
+
+// String returns a human readable name of the reason.
+func (r WarningReason) String() string {
+	switch r {
+	case WarningInversion:
+		return "inversion"
+	case WarningNoRuleMatched:
+		return "no-rule-matched"
+	case WarningNounEnding:
+		return "noun-ending"
+	case WarningLiteraryEnding:
+		return "literary-ending"
+	default:
+		return "unknown"
+	}
+}
+
+// Warning describes a sentence that was left unchanged because its structure
+// could not be handled reliably.
+type Warning struct {
+	SentenceIndex int           // 文の番号（0始まり）
+	Reason        WarningReason // 警告の理由
+	Text          string        // 対象の文（原文のまま）
+}
+
+// isInverted reports whether the sentence is inverted (倒置法), that is, a predicate
+// is followed by a 読点 and a trailing phrase that ends with a case or binding
+// particle, e.g. 行くよ、明日は。
+func isInverted(morphemes []MorphemeInfo) bool {
+	return invertedComma(morphemes) >= 0
+}
+
+// invertedComma returns the index of the 読点 after the predicate of an
+// inverted sentence, or -1 if the sentence is not inverted.
+func invertedComma(morphemes []MorphemeInfo) int {
+	end := len(morphemes) - 1
+	for end >= 0 && morphemes[end].PartOfSpeech == "記号" {
+		end--
+	}
+	if end < 0 || morphemes[end].PartOfSpeech != "助詞" {
+		return -1
+	}
+	switch morphemes[end].PartOfSpeechDetail1 {
+	case "格助詞", "係助詞", "副助詞":
+	default:
+		return -1
+	}
+
+	// Find the last 読点; the trailing phrase must consist of nouns and particles
+	comma := end
+	for comma >= 0 && morphemes[comma].PartOfSpeechDetail1 != "読点" {
+		switch morphemes[comma].PartOfSpeech {
+		case "名詞", "助詞", "接頭詞", "連体詞":
+		default:
+			return -1
+		}
+		comma--
+	}
+	if comma <= 0 {
+		return -1
+	}
+
+	// The phrase before the 読点 must end with a predicate
+	i := comma - 1
+	for i >= 0 && isSentenceFinalParticle(morphemes[i]) {
+		i--
+	}
+	if i < 0 {
+		return -1
+	}
+	switch morphemes[i].PartOfSpeech {
+	case "動詞", "形容詞", "助動詞":
+		return comma
+	}
+	return -1
+}
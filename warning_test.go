@@ -0,0 +1,160 @@
+package kjconv
+
+import (
+	"testing"
+)
+
+func TestIsInverted(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected bool
+	}{
+		{
+			name:     "動詞＋終助詞の倒置",
+			input:    "行くよ、明日は。",
+			expected: true,
+		},
+		{
+			name:     "形容詞の倒置",
+			input:    "美味しいね、このケーキは。",
+			expected: true,
+		},
+		{
+			name:     "助動詞の倒置",
+			input:    "来たんだ、彼が。",
+			expected: true,
+		},
+		{
+			name:     "通常の文",
+			input:    "明日は学校に行く。",
+			expected: false,
+		},
+		{
+			name:     "接続助詞の後の読点",
+			input:    "雨が降ったので、明日は。",
+			expected: false,
+		},
+		{
+			name:     "読点のない文",
+			input:    "明日は。",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			morphemes, err := converter.AnalyzeMorphemes(tt.input)
+			if err != nil {
+				t.Fatalf("AnalyzeMorphemes() failed: %v", err)
+			}
+			if result := isInverted(morphemes); result != tt.expected {
+				t.Errorf("isInverted(%q) = %v, expected %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestConvertDetailed_Warnings(t *testing.T) {
+	converter, err := NewConverter()
+	if err != nil {
+		t.Fatalf("NewConverter() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		mode     ConversionMode
+		expected string
+		warnings []Warning
+	}{
+		{
+			name:     "倒置法は原文を維持して警告",
+			input:    "本を読む。行くよ、明日は。",
+			mode:     CasualToPolite,
+			expected: "本を読みます。行くよ、明日は。",
+			warnings: []Warning{
+				{SentenceIndex: 1, Reason: WarningInversion, Text: "行くよ、明日は。"},
+			},
+		},
+		{
+			name:     "変換先の文体の倒置法は警告しない",
+			input:    "行きますよ、明日は。",
+			mode:     CasualToPolite,
+			expected: "行きますよ、明日は。",
+		},
+		{
+			name:     "常体への変換でも敬体の倒置法は警告",
+			input:    "行きますよ、明日は。",
+			mode:     PoliteToCasual,
+			expected: "行きますよ、明日は。",
+			warnings: []Warning{
+				{SentenceIndex: 0, Reason: WarningInversion, Text: "行きますよ、明日は。"},
+			},
+		},
+		{
+			name:     "ルールに合致しない文末",
+			input:    "本を読んで。本を読む。",
+			mode:     CasualToPolite,
+			expected: "本を読んで。本を読みます。",
+			warnings: []Warning{
+				{SentenceIndex: 0, Reason: WarningNoRuleMatched, Text: "本を読んで。"},
+			},
+		},
+		{
+			name:     "述語のない文は警告しない",
+			input:    "え…ありがとう。明日は。（笑）",
+			mode:     CasualToPolite,
+			expected: "え…ありがとう。明日は。（笑）",
+		},
+		{
+			name:     "述語のない文は常体への変換でも警告しない",
+			input:    "え…ありがとう。明日は。（笑）",
+			mode:     PoliteToCasual,
+			expected: "え…ありがとう。明日は。（笑）",
+		},
+		{
+			name:     "変換先の文体の文は警告しない",
+			input:    "本を読みます。",
+			mode:     CasualToPolite,
+			expected: "本を読みます。",
+		},
+		{
+			name:     "依頼の敬体は警告しない",
+			input:    "本を読んでください。",
+			mode:     CasualToPolite,
+			expected: "本を読んでください。",
+		},
+		{
+			name:     "引用文は警告しない",
+			input:    "「今日は晴れだ」",
+			mode:     CasualToPolite,
+			expected: "「今日は晴れだ」",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := converter.ConvertDetailed(tt.input, tt.mode)
+			if err != nil {
+				t.Fatalf("ConvertDetailed() failed: %v", err)
+			}
+			if result.Text != tt.expected {
+				t.Errorf("ConvertDetailed().Text = %q, expected %q", result.Text, tt.expected)
+			}
+			if len(result.Warnings) != len(tt.warnings) {
+				t.Fatalf("ConvertDetailed() returned %d warnings, expected %d: %v", len(result.Warnings), len(tt.warnings), result.Warnings)
+			}
+			for i, w := range result.Warnings {
+				if w != tt.warnings[i] {
+					t.Errorf("Warnings[%d] = %+v, expected %+v", i, w, tt.warnings[i])
+				}
+			}
+		})
+	}
+}